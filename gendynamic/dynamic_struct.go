@@ -1,7 +1,6 @@
 package gendynamic
 
 import (
-	"errors"
 	"fmt"
 	"strings"
 	"unicode"
@@ -72,6 +71,41 @@ func NewStructFieldWithReflectType(fieldName string, fieldType safereflect.Type,
 	return safereflect.StructField{Name: fieldName, Type: fieldType, Tag: safereflect.StructTag(fieldTag)}
 }
 
+// NewStructFieldWithDerivedTags is used to create new struct fields to be used with NewStructDefinition as arguments. It works like
+// NewStructFieldWithReflectTagAndType, and additionally derives a tag entry for each of the tagKeys, such as "json" or "yaml", that is
+// not already present in fieldTag. The derived entries use the fieldName as it was passed in, before it was capitalized, so a field
+// created with the name "userId" and the key "json" gets the name "UserId" and the tag `json:"userId"`. Entries already present in
+// fieldTag are kept as they are. This function returns an error if fieldTag is malformed or one of the tagKeys is not a valid tag key.
+func NewStructFieldWithDerivedTags(fieldName string, fieldType safereflect.Type, fieldTag safereflect.StructTag, tagKeys ...string) (safereflect.StructField, error) {
+	tagName := strings.ReplaceAll(fieldName, " ", "")
+	field := NewStructFieldWithReflectTagAndType(fieldName, fieldType, fieldTag)
+	for _, key := range tagKeys {
+		if _, ok := field.Tag.Lookup(key); ok {
+			continue
+		}
+		tag, err := field.Tag.Set(key, tagName)
+		if err != nil {
+			return safereflect.StructField{}, fmt.Errorf("could not derive %s tag for field with name: \"%s\": %w", key, fieldName, err)
+		}
+		field.Tag = tag
+	}
+	return field, nil
+}
+
+// NewStructFieldWithTypeExpr is used to create new struct fields to be used with NewStructDefinition as arguments. This function
+// takes a fieldName string, typeExpr string, fieldTag string, and a registry as arguments. The field must be exported, therefore the
+// first letter of the fieldName will automatically be capitalized if it is not already capitalized. typeExpr is the type of the field
+// written in Go syntax, for example "string", "[]int" or "map[string]*Order", so the type can come from configuration rather than
+// from a sample value. Names other than the predeclared types are looked up in the registry, which may be nil if there are none. See
+// safereflect.ParseType for the supported syntax. This function returns an error if typeExpr can not be parsed.
+func NewStructFieldWithTypeExpr(fieldName string, typeExpr string, fieldTag string, registry *safereflect.Registry) (safereflect.StructField, error) {
+	fieldType, err := safereflect.ParseType(typeExpr, registry)
+	if err != nil {
+		return safereflect.StructField{}, fmt.Errorf("could not parse type for field with name: \"%s\": %w", fieldName, err)
+	}
+	return NewStructFieldWithReflectType(fieldName, fieldType, fieldTag), nil
+}
+
 // NewStructDefinition takes a variadic of fields which are reflect.StructField. reflect.StructField can be created by
 // calling the NewStructField function. NewStructDefinition creates a reflect.Type which can be used in the NewTypeInstance,
 // NewSliceOfType, and NewMapOfType functions.
@@ -111,6 +145,21 @@ func NewSliceOfType(typeDefinition safereflect.Type) (any, error) {
 	return newSlice.Interface()
 }
 
+// NewChanOfType is used with a typeDefinition. typeDefinition is a reflect.Type which can be created by using the NewStructDefinition
+// function. NewChanOfType creates a bidirectional channel of the type specified with the given buffer size. This function can be used
+// to create channels of dynamic types. This function can be used with any reflect.Type.
+func NewChanOfType(typeDefinition safereflect.Type, buffer int) (any, error) {
+	cTyp, err := safereflect.ChanOf(safereflect.BothDir, typeDefinition)
+	if err != nil {
+		return nil, err
+	}
+	newChan, err := safereflect.MakeChan(cTyp, buffer)
+	if err != nil {
+		return nil, err
+	}
+	return newChan.Interface()
+}
+
 // NewMapOfType is used with a keyType (comparable) and a typeDefinition. keyType must implement the comparable interface. typeDefinition is a reflect.Type
 // which can be created by using the NewStructDefinition function. NewMapOfType creates a pointer to a map of the type specified.
 // This function can create instances of maps of dynamic types. This function can be used with any reflect.Type.
@@ -139,14 +188,14 @@ func NewMapOfType[T comparable](keyType T, typeDefinition safereflect.Type) (any
 // dynamic types. This function can be used with any reflect.Type.
 func NewMapOfTypeWithReflectTypeKey(keyType safereflect.Type, typeDefinition safereflect.Type) (any, error) {
 	if !keyType.Comparable() {
-		return nil, errors.New("keyType is not comparable")
+		return nil, fmt.Errorf("keyType is not comparable: %w", safereflect.ErrWrongType)
 	}
 	si, err := NewTypeInstance(typeDefinition)
 	if err != nil {
 		return nil, err
 	}
 	sd := safereflect.ValueOf(&si).Type().Elem()
-	mTyp, err := safereflect.MapOf(safereflect.TypeOf(keyType), sd)
+	mTyp, err := safereflect.MapOf(keyType, sd)
 	if err != nil {
 		return nil, err
 	}
@@ -160,27 +209,31 @@ func NewMapOfTypeWithReflectTypeKey(keyType safereflect.Type, typeDefinition saf
 // SetStructFieldValue takes a typeInstance (generic/dynamic struct or reflect created instance), a fieldName string to specify the field that to set
 // the value of, and the value to set the field to. The typeInstance passed to this function must be a pointer to a type instance. Instances created
 // by the NewTypeInstance function are pointers. If the type instance is not a pointer to a struct instance, the fieldName does not exist on this
-// typeInstance, or the underlying type of the field does not match the type of the fieldValue this function returns an error.
-func SetStructFieldValue[T any](typeInstance any, fieldName string, fieldValue T) error {
+// typeInstance, or the underlying type of the field does not match the type of the fieldValue this function returns an error. Passing
+// safereflect.WithSetMode relaxes that last requirement, allowing values that are assignable or losslessly convertible to the type of the field.
+func SetStructFieldValue[T any](typeInstance any, fieldName string, fieldValue T, opts ...safereflect.SetOption) error {
 	e, err := safereflect.ValueOf(typeInstance).Elem()
 	if err != nil {
 		return err
 	}
 	if safereflect.ValueOf(typeInstance).Kind() != safereflect.Pointer || e.Kind() != safereflect.Struct {
-		return fmt.Errorf("expected a pointer to a struct instance, got %s", e.Kind())
+		return fmt.Errorf("expected a pointer to a struct instance, got %s: %w", e.Kind(), safereflect.ErrWrongKind)
 	}
-	efn, err := e.FieldByName(fieldName)
+	efn, err := structField(e, fieldName)
 	if err != nil {
-		return fmt.Errorf("field with name: \"%s\" does not exist on struct instance", fieldName)
+		return err
 	}
-	if !efn.IsValid() {
-		return fmt.Errorf("field with name: \"%s\" does not exist on struct instance", fieldName)
+	if mode := safereflect.ApplySetOptions(opts...).Mode; mode != safereflect.SetModeExact {
+		if err := efn.SetWithMode(safereflect.ValueOf(fieldValue), mode); err != nil {
+			return fmt.Errorf("field with name: \"%s\" can not be set: %w", fieldName, err)
+		}
+		return nil
 	}
 	if safereflect.ValueOf(fieldValue).Type().Kind() != efn.Kind() {
 		if safereflect.TypeFor[T]().Kind() == safereflect.Interface {
 			return efn.Set(safereflect.ValueOf(fieldValue))
 		}
-		return fmt.Errorf("field with name: \"%s\" has underlying type: %s, but fieldValue argument has type: %s", fieldName, efn.Kind().String(), safereflect.TypeFor[T]().Kind().String())
+		return fmt.Errorf("field with name: \"%s\" has underlying type: %s, but fieldValue argument has type: %s: %w", fieldName, efn.Kind().String(), safereflect.TypeFor[T]().Kind().String(), safereflect.ErrWrongType)
 	}
 	return efn.Set(safereflect.ValueOf(fieldValue))
 }
@@ -198,23 +251,20 @@ func GetStructFieldValue[T any](typeInstance any, fieldName string) (T, error) {
 		}
 	}
 	if val.Kind() != safereflect.Struct {
-		return safereflect.ZeroGeneric[T](), errors.New("expected a struct instance")
+		return safereflect.ZeroGeneric[T](), fmt.Errorf("expected a struct instance: %w", safereflect.ErrWrongKind)
 	}
-	fn, err := val.FieldByName(fieldName)
+	fn, err := structField(val, fieldName)
 	if err != nil {
-		return safereflect.ZeroGeneric[T](), errors.New("field not valid for struct instance")
-	}
-	if !fn.IsValid() {
-		return safereflect.ZeroGeneric[T](), errors.New("field not valid for struct instance")
+		return safereflect.ZeroGeneric[T](), err
 	}
 	if fn.Kind() != safereflect.TypeFor[T]().Kind() {
-		return safereflect.ZeroGeneric[T](), fmt.Errorf("field with name: \"%s\" has underlying type: %s, but generic type assertion was for type: %s", fieldName, fn.Kind().String(), safereflect.TypeFor[T]().Kind().String())
+		return safereflect.ZeroGeneric[T](), fmt.Errorf("field with name: \"%s\" has underlying type: %s, but generic type assertion was for type: %s: %w", fieldName, fn.Kind().String(), safereflect.TypeFor[T]().Kind().String(), safereflect.ErrWrongType)
 	}
 	fni, err := fn.Interface()
 	if err != nil {
 		return safereflect.ZeroGeneric[T](), err
 	}
-	return fni.(T), nil
+	return Assert[T](fni)
 }
 
 // GetStructFieldValueAny is like GetStructFieldValue, however, it doesn't take a generic T. This function returns The value on the typeInstance of the fieldName specified.
@@ -230,14 +280,26 @@ func GetStructFieldValueAny(typeInstance any, fieldName string) (any, error) {
 		}
 	}
 	if val.Kind() != safereflect.Struct {
-		return nil, errors.New("expected a struct instance")
+		return nil, fmt.Errorf("expected a struct instance: %w", safereflect.ErrWrongKind)
 	}
-	fn, err := val.FieldByName(fieldName)
+	fn, err := structField(val, fieldName)
 	if err != nil {
-		return nil, errors.New("field not valid for struct instance")
-	}
-	if !fn.IsValid() {
-		return nil, errors.New("field not valid for struct instance")
+		return nil, err
 	}
 	return fn.Interface()
 }
+
+// structField returns the field fieldName of the struct value val. Fields promoted from embedded
+// structs are resolved too, and an ambiguous name or a nil embedded pointer is reported as an error. fieldName is a single field
+// name, not a path. It is resolved through safereflect.CachedFieldAccessor, so it is usually only looked up once for every struct type.
+func structField(val safereflect.Value, fieldName string) (safereflect.Value, error) {
+	acc, err := safereflect.CachedFieldAccessor(val.Type(), fieldName)
+	if err != nil {
+		return safereflect.Value{}, fmt.Errorf("field with name: \"%s\" is not valid for struct instance: %w", fieldName, err)
+	}
+	fn, err := acc.Get(val)
+	if err != nil {
+		return safereflect.Value{}, fmt.Errorf("field with name: \"%s\" can not be reached on struct instance: %w", fieldName, err)
+	}
+	return fn, nil
+}
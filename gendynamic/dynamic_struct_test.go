@@ -0,0 +1,236 @@
+package gendynamic
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/gcottom/refract/safereflect"
+)
+
+func TestNewChanOfType(t *testing.T) {
+	def, err := NewStructDefinition(NewStructField("name", "", `json:"name"`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		typ    safereflect.Type
+		buffer int
+		want   string
+	}{
+		{name: "dynamic struct", typ: def, buffer: 2, want: "chan struct { Name string \"json:\\\"name\\\"\" }"},
+		{name: "builtin type", typ: safereflect.TypeOf(0), buffer: 0, want: "chan int"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ch, err := NewChanOfType(tt.typ, tt.buffer)
+			if err != nil {
+				t.Fatalf("NewChanOfType() error = %v", err)
+			}
+			v := safereflect.ValueOf(ch)
+			if got := v.Type().String(); got != tt.want {
+				t.Errorf("type = %s, want %s", got, tt.want)
+			}
+			if c := v.V.Cap(); c != tt.buffer {
+				t.Errorf("cap = %d, want %d", c, tt.buffer)
+			}
+		})
+	}
+}
+
+type Base struct {
+	ID int
+}
+
+type Named struct {
+	Name string
+}
+
+type Label struct {
+	Name string
+}
+
+type Record struct {
+	Base
+	*Named
+	Label
+	Size int
+}
+
+func TestPromotedFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		rec     *Record
+		field   string
+		want    any
+		wantErr error
+	}{
+		{name: "declared", rec: &Record{Size: 2}, field: "Size", want: 2},
+		{name: "promoted", rec: &Record{Base: Base{ID: 7}}, field: "ID", want: 7},
+		{name: "ambiguous", rec: &Record{Named: &Named{}}, field: "Name", wantErr: safereflect.ErrAmbiguous},
+		{name: "missing", rec: &Record{}, field: "Missing", wantErr: safereflect.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetStructFieldValueAny(tt.rec, tt.field)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetStructFieldValueAny() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetStructFieldValueAny() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetStructFieldValueAny() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("set promoted", func(t *testing.T) {
+		rec := &Record{}
+		if err := SetStructFieldValue(rec, "ID", 9); err != nil {
+			t.Fatalf("SetStructFieldValue() error = %v", err)
+		}
+		if rec.ID != 9 {
+			t.Errorf("ID = %d, want 9", rec.ID)
+		}
+	})
+}
+
+func TestSetStructFieldValueModes(t *testing.T) {
+	def, err := NewStructDefinition(
+		NewStructField("Count", int64(0), ""),
+		NewStructFieldWithReflectType("Reader", safereflect.TypeFor[io.Reader](), ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		field   string
+		value   any
+		opts    []safereflect.SetOption
+		wantErr error
+	}{
+		{name: "exact", field: "Count", value: int64(1)},
+		{name: "exact rejects int", field: "Count", value: 1, wantErr: safereflect.ErrWrongType},
+		{name: "convert int", field: "Count", value: 1, opts: []safereflect.SetOption{safereflect.WithSetMode(safereflect.SetModeConvert)}},
+		{name: "convert rejects truncation", field: "Count", value: 1.5, opts: []safereflect.SetOption{safereflect.WithSetMode(safereflect.SetModeConvert)}, wantErr: safereflect.ErrTruncated},
+		{name: "assignable reader", field: "Reader", value: &bytes.Buffer{}, opts: []safereflect.SetOption{safereflect.WithSetMode(safereflect.SetModeAssignable)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst, err := NewTypeInstance(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = SetStructFieldValue(inst, tt.field, tt.value, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetStructFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStructFieldWithDerivedTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   string
+		tag     safereflect.StructTag
+		keys    []string
+		want    safereflect.StructTag
+		wantErr error
+	}{
+		{name: "derived", field: "userId", keys: []string{"json", "yaml"}, want: `json:"userId" yaml:"userId"`},
+		{name: "existing entry kept", field: "userId", tag: `json:"uid,omitempty"`, keys: []string{"json", "db"}, want: `json:"uid,omitempty" db:"userId"`},
+		{name: "spaces removed", field: "user id", keys: []string{"json"}, want: `json:"userid"`},
+		{name: "no keys", field: "userId", tag: `json:"a"`, want: `json:"a"`},
+		{name: "malformed tag", field: "userId", tag: `json:"a`, keys: []string{"yaml"}, wantErr: safereflect.ErrInvalidTag},
+		{name: "invalid key", field: "userId", keys: []string{"a:b"}, wantErr: safereflect.ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewStructFieldWithDerivedTags(tt.field, safereflect.TypeOf(0), tt.tag, tt.keys...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewStructFieldWithDerivedTags() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewStructFieldWithDerivedTags() error = %v", err)
+			}
+			if f.Tag != tt.want {
+				t.Errorf("tag = %s, want %s", f.Tag, tt.want)
+			}
+		})
+	}
+}
+
+func TestLiteralFieldNames(t *testing.T) {
+	type inner struct{ B int }
+	type outer struct{ A inner }
+	v := &outer{A: inner{B: 1}}
+	tests := []struct {
+		name    string
+		field   string
+		wantErr error
+	}{
+		{name: "field", field: "A"},
+		{name: "dotted name", field: "A.B", wantErr: safereflect.ErrNotFound},
+		{name: "indexed name", field: "A[0]", wantErr: safereflect.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := GetStructFieldValueAny(v, tt.field)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetStructFieldValueAny() error = %v, want %v", err, tt.wantErr)
+			}
+			if err := SetStructFieldValue(v, tt.field, 2); tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetStructFieldValue() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewStructFieldWithTypeExpr(t *testing.T) {
+	registry := safereflect.NewRegistry()
+	if err := registry.Register("Base", safereflect.TypeOf(Base{})); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		expr    string
+		want    string
+		wantErr error
+	}{
+		{name: "builtin", expr: "[]int", want: "[]int"},
+		{name: "registered", expr: "map[string]*Base", want: "map[string]*gendynamic.Base"},
+		{name: "unknown name", expr: "Order", wantErr: safereflect.ErrNotFound},
+		{name: "syntax error", expr: "map[string", wantErr: safereflect.ErrInvalidTypeExpr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewStructFieldWithTypeExpr("items", tt.expr, `json:"items"`, registry)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("NewStructFieldWithTypeExpr() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewStructFieldWithTypeExpr() error = %v", err)
+			}
+			if f.Name != "Items" || f.Type.String() != tt.want || f.Tag != `json:"items"` {
+				t.Errorf("field = %s %v %s, want Items %s", f.Name, f.Type, f.Tag, tt.want)
+			}
+			if _, err := NewStructDefinition(f); err != nil {
+				t.Errorf("NewStructDefinition() error = %v", err)
+			}
+		})
+	}
+}
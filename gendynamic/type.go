@@ -12,7 +12,7 @@ func Assert[T any](v any) (T, error) {
 	if ok {
 		return out, nil
 	}
-	return safereflect.ZeroGeneric[T](), fmt.Errorf("couldn't assert as %s", reflect.TypeFor[T]().String())
+	return safereflect.ZeroGeneric[T](), fmt.Errorf("couldn't assert as %s: %w", reflect.TypeFor[T]().String(), safereflect.ErrWrongType)
 }
 
 func GetReflectType(v any) safereflect.Type {
@@ -3,6 +3,7 @@ package godict
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 
 	"github.com/gcottom/refract/safereflect"
 )
@@ -19,7 +20,7 @@ func UnmarshalSingleJSONKey[T any](key string, data []byte) (T, error) {
 	if out, ok := val.(T); ok {
 		return out, nil
 	} else {
-		return safereflect.ZeroGeneric[T](), errors.New("value not of type specified")
+		return safereflect.ZeroGeneric[T](), fmt.Errorf("value not of type specified: %w", safereflect.ErrWrongType)
 	}
 
 }
@@ -35,7 +36,10 @@ func UnmarshalSingleJSONKeyIntoPtr(key string, data []byte, v any) error {
 	}
 	e, err := safereflect.ValueOf(v).Elem()
 	if err != nil {
-		return err
+		return fmt.Errorf("v should be a pointer: %w", err)
+	}
+	if err := e.Set(safereflect.ValueOf(val)); err != nil {
+		return fmt.Errorf("unable to set value of key %q: %w", key, err)
 	}
-	return e.Set(safereflect.ValueOf(val))
+	return nil
 }
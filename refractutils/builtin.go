@@ -1,9 +1,10 @@
 package refractutils
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
+
+	"github.com/gcottom/refract/safereflect"
 )
 
 // Append is like the native go function append(). The refract.Append function takes a slice of any and variadic elems.
@@ -16,7 +17,7 @@ func Append(slice any, elems ...any) (any, error) {
 	}
 	if val.Kind() != reflect.Slice {
 		// if slice is not a slice, return an error
-		return slice, errors.New("slice argument was not a slice")
+		return slice, fmt.Errorf("slice argument was not a slice: %w", safereflect.ErrWrongKind)
 	}
 	// get the reflect type of a pointer to the slice
 	vtype := reflect.ValueOf(&slice)
@@ -75,7 +76,7 @@ func Cap(v any) (int, error) {
 		// if the kind of v is not array, chan, or slice, return an error, otherwise return the cap
 		return val.Cap(), nil
 	}
-	return 0, fmt.Errorf("kind: %v not supported by cap function", kind.String())
+	return 0, fmt.Errorf("kind: %v not supported by cap function: %w", kind.String(), safereflect.ErrWrongKind)
 }
 
 // Len is like the native len() function. It accepts an argument of any v, so it works with generic and dynamic types.
@@ -87,7 +88,7 @@ func Len(v any) (int, error) {
 		// if the kind of v is not array, chan, slice, map, or string, return an error, otherwise return the length
 		return val.Len(), nil
 	}
-	return 0, fmt.Errorf("kind: %v not supported by len function", kind.String())
+	return 0, fmt.Errorf("kind: %v not supported by len function: %w", kind.String(), safereflect.ErrWrongKind)
 }
 
 // Prepend is the opposite of Append. Instead of adding elements at the end of the slice, it adds them at the beginning, in the order they appear in the variatic.
@@ -100,7 +101,7 @@ func Prepend(slice any, elems ...any) (any, error) {
 	}
 	if val.Kind() != reflect.Slice {
 		// if slice is not a slice return error
-		return slice, errors.New("slice argument was not a slice")
+		return slice, fmt.Errorf("slice argument was not a slice: %w", safereflect.ErrWrongKind)
 	}
 
 	// count all of the elems in the variadic
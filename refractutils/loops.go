@@ -41,21 +41,24 @@ func RangeOverSliceReverse(slice any, f func(index int, sliceItem any)) error {
 }
 
 func RangeOverMap(m any, f func(counter int, key any, value any)) error {
-	val := safereflect.ValueOf(m)
-	mk, err := val.MapKeys()
+	iter, err := safereflect.ValueOf(m).MapRange()
 	if err != nil {
 		return err
 	}
-	for counter, k := range mk {
+	for counter := 0; iter.Next(); counter++ {
+		k, err := iter.Key()
+		if err != nil {
+			return err
+		}
 		key, err := k.Interface()
 		if err != nil {
 			return err
 		}
-		vmi, err := val.MapIndex(k)
+		v, err := iter.Value()
 		if err != nil {
 			return err
 		}
-		value, err := vmi.Interface()
+		value, err := v.Interface()
 		if err != nil {
 			return err
 		}
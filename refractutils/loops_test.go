@@ -0,0 +1,51 @@
+package refractutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/gcottom/refract/safereflect"
+)
+
+func TestRangeOverMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       any
+		want    map[any]any
+		wantErr error
+	}{
+		{name: "map", m: map[string]int{"a": 1, "b": 2}, want: map[any]any{"a": 1, "b": 2}},
+		{name: "empty map", m: map[string]int{}, want: map[any]any{}},
+		{name: "nil map", m: map[string]int(nil), want: map[any]any{}},
+		{name: "not a map", m: []int{1}, wantErr: safereflect.ErrWrongKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := make(map[any]any)
+			var counters []int
+			err := RangeOverMap(tt.m, func(counter int, key, value any) {
+				counters = append(counters, counter)
+				got[key] = value
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("RangeOverMap() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("RangeOverMap() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("RangeOverMap() visited %v, want %v", got, tt.want)
+			}
+			for i, c := range counters {
+				if c != i {
+					t.Errorf("counters = %v, want 0..%d", counters, len(counters)-1)
+					break
+				}
+			}
+		})
+	}
+}
@@ -1,7 +1,7 @@
 package refractutils
 
 import (
-	"errors"
+	"fmt"
 
 	"github.com/gcottom/refract/safereflect"
 )
@@ -11,9 +11,14 @@ func GetMapIndex(m any, key any) (any, error) {
 	if val.Kind() == safereflect.Map {
 		return val.MapIndex(safereflect.ValueOf(key))
 	}
-	return nil, errors.New("map argument was not a map")
+	return nil, fmt.Errorf("map argument was not a map: %w", safereflect.ErrWrongKind)
 }
 
+// GetMapIndexValue takes a map of any type and a key. It returns a deep copy of the value stored under the key,
+// made with safereflect.DeepCopy, so the copy has the type of the stored value but shares no pointers, maps or
+// slices with the map and can safely be handed to another goroutine. Unexported struct fields, such as the state of a
+// time.Time, are copied shallowly. A stored pointer is returned as a pointer to a copy of the value it refers to. This
+// function returns an error if the argument is not a map.
 func GetMapIndexValue(m any, key any) (any, error) {
 	val := safereflect.ValueOf(m)
 	if val.Kind() == safereflect.Map {
@@ -25,9 +30,9 @@ func GetMapIndexValue(m any, key any) (any, error) {
 		if err != nil {
 			return nil, err
 		}
-		return safereflect.ValueOf(keyValPtrI).Interface()
+		return safereflect.DeepCopy(keyValPtrI, safereflect.CopyUnexported(safereflect.UnexportedShallow))
 	}
-	return nil, errors.New("map argument was not a map")
+	return nil, fmt.Errorf("map argument was not a map: %w", safereflect.ErrWrongKind)
 }
 
 func PutMapIndex(m any, key any, value any) error {
@@ -43,5 +48,5 @@ func PutMapIndex(m any, key any, value any) error {
 		}
 		return val.SetMapIndex(safereflect.ValueOf(key), nval)
 	}
-	return errors.New("map argument was not a map")
+	return fmt.Errorf("map argument was not a map: %w", safereflect.ErrWrongKind)
 }
@@ -0,0 +1,69 @@
+package refractutils
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gcottom/refract/safereflect"
+)
+
+type address struct {
+	City  string
+	Lines []string
+}
+
+type event struct {
+	Name string
+	At   time.Time
+}
+
+func TestGetMapIndexValue(t *testing.T) {
+	stored := &address{City: "oslo", Lines: []string{"a"}}
+	m := map[string]*address{"home": stored}
+
+	got, err := GetMapIndexValue(m, "home")
+	if err != nil {
+		t.Fatalf("GetMapIndexValue() error = %v", err)
+	}
+	c, ok := got.(*address)
+	if !ok {
+		t.Fatalf("GetMapIndexValue() = %T, want the stored type *address", got)
+	}
+	if c == stored || !reflect.DeepEqual(c, stored) {
+		t.Fatalf("GetMapIndexValue() = %+v, want an equal copy", c)
+	}
+	c.City = "bergen"
+	c.Lines[0] = "b"
+	if stored.City != "oslo" || stored.Lines[0] != "a" {
+		t.Errorf("modifying the copy changed the stored value: %+v", stored)
+	}
+
+	values := map[int][]int{1: {1, 2}}
+	got, err = GetMapIndexValue(values, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got.([]int)[0] = 9
+	if values[1][0] != 1 {
+		t.Error("the copied slice shares memory with the stored one")
+	}
+
+	if _, err := GetMapIndexValue([]int{}, 0); !errors.Is(err, safereflect.ErrWrongKind) {
+		t.Errorf("GetMapIndexValue() on a slice error = %v, want %v", err, safereflect.ErrWrongKind)
+	}
+}
+
+func TestGetMapIndexValueUnexportedState(t *testing.T) {
+	at := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	m := map[string]event{"launch": {Name: "launch", At: at}}
+
+	got, err := GetMapIndexValue(m, "launch")
+	if err != nil {
+		t.Fatalf("GetMapIndexValue() error = %v", err)
+	}
+	if e := got.(event); !e.At.Equal(at) {
+		t.Errorf("GetMapIndexValue().At = %v, want %v", e.At, at)
+	}
+}
@@ -1,7 +1,6 @@
 package refractutils
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/gcottom/refract/safereflect"
@@ -18,7 +17,7 @@ func GetSliceIndex(slice any, index int) (any, error) {
 			return nil, err
 		}
 		if index > length-1 {
-			return nil, fmt.Errorf("index: %d is out of range for slice with length: %d", index, length)
+			return nil, fmt.Errorf("index: %d is out of range for slice with length: %d: %w", index, length, safereflect.ErrOutOfRange)
 		}
 		vi, err := val.Index(index)
 		if err != nil {
@@ -26,12 +25,15 @@ func GetSliceIndex(slice any, index int) (any, error) {
 		}
 		return vi.Interface()
 	}
-	return nil, errors.New("slice argument was not a slice")
+	return nil, fmt.Errorf("slice argument was not a slice: %w", safereflect.ErrWrongKind)
 }
 
 // GetSliceIndexValue takes a slice of any type and an index int. For dynamic types created with refract,
 // it returns a copy of the item at the index (this copy will not be modifiable), otherwise returns the
-// value at the index. This function returns an error if the index is out of bounds or the argument is not a slice.
+// value at the index. The copy is a deep copy made with safereflect.DeepCopy, so it shares no pointers, maps
+// or slices with the slice and can safely be handed to another goroutine. Unexported struct fields, such as the
+// state of a time.Time, are copied shallowly. This function returns an error if the index is out of bounds or the
+// argument is not a slice.
 func GetSliceIndexValue(slice any, index int) (any, error) {
 	val := safereflect.ValueOf(slice)
 	if val.Kind() == safereflect.Slice {
@@ -40,7 +42,7 @@ func GetSliceIndexValue(slice any, index int) (any, error) {
 			return nil, err
 		}
 		if index > length-1 {
-			return nil, fmt.Errorf("index: %d is out of range for slice with length: %d", index, length)
+			return nil, fmt.Errorf("index: %d is out of range for slice with length: %d: %w", index, length, safereflect.ErrOutOfRange)
 		}
 		vi, err := val.Index(index)
 		if err != nil {
@@ -55,16 +57,19 @@ func GetSliceIndexValue(slice any, index int) (any, error) {
 			if err != nil {
 				return nil, err
 			}
-			return vptre.Interface()
+			if vptr, err = vptre.Interface(); err != nil {
+				return nil, err
+			}
 		}
-		return vptr, nil
+		return safereflect.DeepCopy(vptr, safereflect.CopyUnexported(safereflect.UnexportedShallow))
 	}
-	return nil, errors.New("slice argument was not a slice")
+	return nil, fmt.Errorf("slice argument was not a slice: %w", safereflect.ErrWrongKind)
 }
 
 // SetSliceIndex takes a slice of any, the new value, and the index to put the new value at. This function returns
-// an error if the index is out of bounds or the slice argument is not a slice
-func SetSliceIndex(slice any, newValue any, index int) error {
+// an error if the index is out of bounds or the slice argument is not a slice. By default the new value must have
+// exactly the element type of the slice; safereflect.WithSetMode allows assignable or losslessly convertible values as well.
+func SetSliceIndex(slice any, newValue any, index int, opts ...safereflect.SetOption) error {
 	val := safereflect.ValueOf(slice)
 	if val.Kind() == safereflect.Slice {
 		length, err := Len(slice)
@@ -72,14 +77,17 @@ func SetSliceIndex(slice any, newValue any, index int) error {
 			return err
 		}
 		if index > length-1 {
-			return fmt.Errorf("index: %d is out of range for slice with length: %d", index, length)
+			return fmt.Errorf("index: %d is out of range for slice with length: %d: %w", index, length, safereflect.ErrOutOfRange)
 		}
 		vi, err := val.Index(index)
 		if err != nil {
 			return err
 		}
 		if !vi.CanSet() {
-			return fmt.Errorf("value at slice index: %d can not be set", index)
+			return fmt.Errorf("value at slice index: %d can not be set: %w", index, safereflect.ErrUnaddressable)
+		}
+		if mode := safereflect.ApplySetOptions(opts...).Mode; mode != safereflect.SetModeExact {
+			return vi.SetWithMode(safereflect.ValueOf(newValue), mode)
 		}
 		nval := safereflect.ValueOf(&newValue)
 		if nval.Kind() == safereflect.Pointer {
@@ -94,5 +102,5 @@ func SetSliceIndex(slice any, newValue any, index int) error {
 		}
 		return vi2.Set(nval)
 	}
-	return errors.New("slice argument was not a slice")
+	return fmt.Errorf("slice argument was not a slice: %w", safereflect.ErrWrongKind)
 }
@@ -0,0 +1,81 @@
+package refractutils
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gcottom/refract/safereflect"
+)
+
+func TestSetSliceIndex(t *testing.T) {
+	convert := safereflect.WithSetMode(safereflect.SetModeConvert)
+	tests := []struct {
+		name    string
+		slice   any
+		value   any
+		index   int
+		opts    []safereflect.SetOption
+		want    any
+		wantErr error
+	}{
+		{name: "exact", slice: []any{1, 2}, value: "x", index: 1, want: "x"},
+		{name: "convert", slice: []int64{1, 2}, value: 7, index: 0, opts: []safereflect.SetOption{convert}, want: int64(7)},
+		{name: "convert overflow", slice: []int8{1}, value: 300, index: 0, opts: []safereflect.SetOption{convert}, wantErr: safereflect.ErrOverflow},
+		{name: "out of range", slice: []any{1}, value: 2, index: 1, wantErr: safereflect.ErrOutOfRange},
+		{name: "not a slice", slice: [1]any{1}, value: 2, index: 0, wantErr: safereflect.ErrWrongKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := SetSliceIndex(tt.slice, tt.value, tt.index, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SetSliceIndex() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetSliceIndex() error = %v", err)
+			}
+			got, err := GetSliceIndexValue(tt.slice, tt.index)
+			if err != nil {
+				t.Fatalf("GetSliceIndexValue() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("slice[%d] = %v, want %v", tt.index, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetSliceIndexValue(t *testing.T) {
+	s := []*address{{City: "oslo", Lines: []string{"a"}}}
+	got, err := GetSliceIndexValue(s, 0)
+	if err != nil {
+		t.Fatalf("GetSliceIndexValue() error = %v", err)
+	}
+	c, ok := got.(address)
+	if !ok {
+		t.Fatalf("GetSliceIndexValue() = %T, want the dereferenced element", got)
+	}
+	c.Lines[0] = "b"
+	if s[0].Lines[0] != "a" {
+		t.Error("modifying the copy changed the slice element")
+	}
+	if _, err := GetSliceIndexValue(s, 1); !errors.Is(err, safereflect.ErrOutOfRange) {
+		t.Errorf("GetSliceIndexValue() error = %v, want %v", err, safereflect.ErrOutOfRange)
+	}
+}
+
+func TestGetSliceIndexValueUnexportedState(t *testing.T) {
+	at := time.Date(2024, 5, 17, 12, 0, 0, 0, time.UTC)
+	s := []*event{{Name: "launch", At: at}}
+
+	got, err := GetSliceIndexValue(s, 0)
+	if err != nil {
+		t.Fatalf("GetSliceIndexValue() error = %v", err)
+	}
+	if e := got.(event); !e.At.Equal(at) {
+		t.Errorf("GetSliceIndexValue().At = %v, want %v", e.At, at)
+	}
+}
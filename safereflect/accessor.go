@@ -0,0 +1,229 @@
+package safereflect
+
+import (
+	"reflect"
+	"sync"
+)
+
+// An Accessor reads and writes the field found by following a path of field names from a struct
+// type, for example Address.City. The path is resolved once by CompileAccessor, so using an
+// Accessor costs little more than a type check and the field accesses themselves, which makes
+// it suited to code that touches the same field of many values. An Accessor is safe for
+// concurrent use.
+type Accessor struct {
+	typ   reflect.Type
+	field reflect.Type
+	path  string
+	steps []accessStep
+}
+
+// An accessStep either follows a pointer or selects a field by its index sequence. seg is the
+// path segment the step was compiled from and is used for error reporting.
+type accessStep struct {
+	seg   pathSegment
+	deref bool
+	index []int
+}
+
+// CompileAccessor resolves path against the struct type t, or a pointer to one, and returns an
+// Accessor for the field it names. The path uses the syntax of Value.Lookup restricted to field
+// names: pointers are followed and promoted fields can be named directly, but indexes and map
+// keys are rejected because they can only be resolved against a value.
+func CompileAccessor(t Type, path string) (*Accessor, error) {
+	const method = "safereflect.CompileAccessor"
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError(method, ErrNilType)
+	}
+	segs, err := parsePath(method, path)
+	if err != nil {
+		return nil, err
+	}
+	if len(segs) == 0 {
+		return nil, &PathError{Method: method, Path: path, Err: argError("reflect.Type.FieldByName", "field name", "empty path")}
+	}
+	return compileAccessor(method, t.ReflectType(), path, segs)
+}
+
+// CompileFieldAccessor is like CompileAccessor, but name is a single field name taken as it
+// is rather than parsed as a path, so a name such as A.B is looked up as a field with that name.
+// Fields promoted from embedded structs are found as by Type.LookupField.
+func CompileFieldAccessor(t Type, name string) (*Accessor, error) {
+	const method = "safereflect.CompileFieldAccessor"
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError(method, ErrNilType)
+	}
+	return compileAccessor(method, t.ReflectType(), name, []pathSegment{{text: name, field: name}})
+}
+
+func compileAccessor(method string, t reflect.Type, path string, segs []pathSegment) (*Accessor, error) {
+	a := &Accessor{typ: t, path: path}
+	cur := a.typ
+	for _, seg := range segs {
+		fail := func(err error) (*Accessor, error) {
+			return nil, &PathError{Method: method, Path: path, Segment: seg.text, Offset: seg.offset, Err: err}
+		}
+		if seg.field == "" {
+			return fail(argError("reflect.Type.FieldByName", "field name", seg.text))
+		}
+		for cur.Kind() == reflect.Pointer {
+			a.steps = append(a.steps, accessStep{seg: seg, deref: true})
+			cur = cur.Elem()
+		}
+		if cur.Kind() != reflect.Struct {
+			return fail(kindError("reflect.Type.FieldByName", "struct", cur.Kind()))
+		}
+		sf, err := toType(cur).LookupField(seg.field)
+		if err != nil {
+			return fail(err)
+		}
+		a.steps = append(a.steps, accessStep{seg: seg, index: sf.Index})
+		cur = sf.Type.ReflectType()
+	}
+	a.field = cur
+	return a, nil
+}
+
+// maxCachedAccessors bounds the number of Accessors kept by CachedAccessor and
+// CachedFieldAccessor. When the cache is full it is emptied, so programs that use a fixed set of
+// paths keep them cached, while paths coming from user input can not make it grow without limit.
+const maxCachedAccessors = 4096
+
+type accessorKey struct {
+	typ   reflect.Type
+	path  string
+	field bool // compiled by CompileFieldAccessor rather than CompileAccessor
+}
+
+// accessors caches the Accessors built by CachedAccessor and CachedFieldAccessor.
+var accessors struct {
+	sync.RWMutex
+	m map[accessorKey]*Accessor
+}
+
+// CachedAccessor is like CompileAccessor, but keeps the Accessors it builds in a cache shared by
+// the whole program, so a type and path are usually only resolved once. It is safe for
+// concurrent use. Paths that can not be resolved are not cached. The cache holds at most a few
+// thousand Accessors and is emptied when it is full; ResetAccessorCache empties it on demand.
+func CachedAccessor(t Type, path string) (*Accessor, error) {
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError("safereflect.CompileAccessor", ErrNilType)
+	}
+	return cachedAccessor(accessorKey{typ: t.ReflectType(), path: path}, func() (*Accessor, error) {
+		return CompileAccessor(t, path)
+	})
+}
+
+// CachedFieldAccessor is like CompileFieldAccessor, but uses the cache of CachedAccessor.
+func CachedFieldAccessor(t Type, name string) (*Accessor, error) {
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError("safereflect.CompileFieldAccessor", ErrNilType)
+	}
+	return cachedAccessor(accessorKey{typ: t.ReflectType(), path: name, field: true}, func() (*Accessor, error) {
+		return CompileFieldAccessor(t, name)
+	})
+}
+
+func cachedAccessor(key accessorKey, compile func() (*Accessor, error)) (*Accessor, error) {
+	accessors.RLock()
+	a, ok := accessors.m[key]
+	accessors.RUnlock()
+	if ok {
+		return a, nil
+	}
+	a, err := compile()
+	if err != nil {
+		return nil, err
+	}
+	accessors.Lock()
+	defer accessors.Unlock()
+	if stored, ok := accessors.m[key]; ok {
+		return stored, nil
+	}
+	if accessors.m == nil || len(accessors.m) >= maxCachedAccessors {
+		accessors.m = make(map[accessorKey]*Accessor)
+	}
+	accessors.m[key] = a
+	return a, nil
+}
+
+// ResetAccessorCache empties the cache used by CachedAccessor and CachedFieldAccessor. Accessors
+// already returned remain valid.
+func ResetAccessorCache() {
+	accessors.Lock()
+	accessors.m = nil
+	accessors.Unlock()
+}
+
+// Type returns the type of the struct, or pointer to struct, the Accessor was compiled for.
+func (a *Accessor) Type() Type {
+	return toType(a.typ)
+}
+
+// FieldType returns the type of the field the Accessor reads and writes.
+func (a *Accessor) FieldType() Type {
+	return toType(a.field)
+}
+
+// Path returns the path the Accessor was compiled from.
+func (a *Accessor) Path() string {
+	return a.path
+}
+
+// Get returns the field of v. v must have the type the Accessor was compiled for, or be a
+// pointer to it. The result is settable when v is a pointer or is itself settable, or when the
+// path passes through a pointer.
+func (a *Accessor) Get(v Value) (Value, error) {
+	return a.get("safereflect.Accessor.Get", v)
+}
+
+// Set assigns x to the field of v. v must be accepted by Get and the field must be settable, so
+// v is usually a pointer. x must be assignable to the type of the field.
+func (a *Accessor) Set(v Value, x Value) error {
+	f, err := a.get("safereflect.Accessor.Set", v)
+	if err != nil {
+		return err
+	}
+	if err := f.SetAssignable(x); err != nil {
+		return &PathError{Method: "safereflect.Accessor.Set", Path: a.path, Err: err}
+	}
+	return nil
+}
+
+func (a *Accessor) get(method string, v Value) (Value, error) {
+	cur := v.V
+	if !cur.IsValid() {
+		return Value{}, newError(method, ErrInvalidValue)
+	}
+	if t := cur.Type(); t != a.typ {
+		if t.Kind() != reflect.Pointer || t.Elem() != a.typ {
+			return Value{}, typeError(method, a.typ, t)
+		}
+		if cur.IsNil() {
+			return Value{}, newError(method, ErrNilValue)
+		}
+		cur = cur.Elem()
+	}
+	for _, s := range a.steps {
+		if s.deref {
+			if cur.IsNil() {
+				e := newError("reflect.Value.Elem", ErrNilValue)
+				e.Actual = cur.Type().String()
+				return Value{}, &PathError{Method: method, Path: a.path, Segment: s.seg.text, Offset: s.seg.offset, Err: e}
+			}
+			cur = cur.Elem()
+			continue
+		}
+		if len(s.index) == 1 {
+			cur = cur.Field(s.index[0])
+			continue
+		}
+		f, err := cur.FieldByIndexErr(s.index)
+		if err != nil {
+			e := newError("reflect.Value.FieldByIndexErr", ErrNilValue)
+			e.Actual = err.Error()
+			return Value{}, &PathError{Method: method, Path: a.path, Segment: s.seg.text, Offset: s.seg.offset, Err: e}
+		}
+		cur = f
+	}
+	return Value{cur}, nil
+}
@@ -0,0 +1,177 @@
+package safereflect
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+type accAddress struct {
+	City string
+}
+
+type accMeta struct {
+	ID int
+}
+
+type accPerson struct {
+	accMeta
+	Name  string
+	Home  accAddress
+	Work  *accAddress
+	Items []string
+}
+
+func TestCompileAccessor(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    any
+		wantErr error
+	}{
+		{path: "Name", want: "ann"},
+		{path: "Home.City", want: "oslo"},
+		{path: "Work.City", want: "bergen"},
+		{path: "ID", want: 7},
+		{path: "accMeta.ID", want: 7},
+		{path: "Missing", wantErr: ErrNotFound},
+		{path: "Home.Missing", wantErr: ErrNotFound},
+		{path: "Name.Len", wantErr: ErrWrongKind},
+		{path: "Items[0]", wantErr: ErrInvalidArgument},
+		{path: "", wantErr: ErrInvalidArgument},
+		{path: "Home.", wantErr: ErrInvalidPath},
+	}
+	p := &accPerson{accMeta: accMeta{ID: 7}, Name: "ann", Home: accAddress{City: "oslo"}, Work: &accAddress{City: "bergen"}}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			a, err := CompileAccessor(TypeOf(accPerson{}), tt.path)
+			if tt.wantErr != nil {
+				var pe *PathError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &pe) {
+					t.Fatalf("CompileAccessor() error = %v, want a *PathError wrapping %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CompileAccessor() error = %v", err)
+			}
+			for _, v := range []Value{ValueOf(p), ValueOf(*p)} {
+				got, err := a.Get(v)
+				if err != nil {
+					t.Fatalf("Get(%v) error = %v", v.Type(), err)
+				}
+				if x, _ := got.Interface(); x != tt.want {
+					t.Errorf("Get(%v) = %v, want %v", v.Type(), x, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAccessorGetSet(t *testing.T) {
+	city, err := CompileAccessor(TypeOf(&accPerson{}), "Work.City")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if city.Type() != TypeOf(&accPerson{}) || city.FieldType() != TypeOf("") || city.Path() != "Work.City" {
+		t.Errorf("Accessor = %v, %v, %s", city.Type(), city.FieldType(), city.Path())
+	}
+
+	p := &accPerson{Work: &accAddress{}}
+	if err := city.Set(ValueOf(p), ValueOf("bergen")); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if p.Work.City != "bergen" {
+		t.Errorf("Work.City = %q, want bergen", p.Work.City)
+	}
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "nil pointer on the path", call: func() error { _, err := city.Get(ValueOf(&accPerson{})); return err }, wantErr: ErrNilValue},
+		{name: "nil root", call: func() error { _, err := city.Get(ValueOf((*accPerson)(nil))); return err }, wantErr: ErrNilValue},
+		{name: "zero Value", call: func() error { _, err := city.Get(Value{}); return err }, wantErr: ErrInvalidValue},
+		{name: "wrong type", call: func() error { _, err := city.Get(ValueOf(accAddress{})); return err }, wantErr: ErrWrongType},
+		{name: "wrong value type", call: func() error { return city.Set(ValueOf(p), ValueOf(1)) }, wantErr: ErrWrongType},
+		{name: "unaddressable", call: func() error {
+			name, _ := CompileAccessor(TypeOf(accPerson{}), "Name")
+			return name.Set(ValueOf(accPerson{}), ValueOf("x"))
+		}, wantErr: ErrUnaddressable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCompileFieldAccessor(t *testing.T) {
+	dotted, err := StructOf([]StructField{{Name: "A", Type: TypeOf(accAddress{})}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		typ     Type
+		field   string
+		wantErr error
+	}{
+		{name: "declared", typ: TypeOf(accPerson{}), field: "Name"},
+		{name: "promoted", typ: TypeOf(accPerson{}), field: "ID"},
+		{name: "dotted name is not a path", typ: dotted, field: "A.City", wantErr: ErrNotFound},
+		{name: "brackets are not an index", typ: TypeOf(accPerson{}), field: "Items[0]", wantErr: ErrNotFound},
+		{name: "nil type", typ: nil, field: "Name", wantErr: ErrNilType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := CompileFieldAccessor(tt.typ, tt.field)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CompileFieldAccessor() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCachedAccessor(t *testing.T) {
+	ResetAccessorCache()
+	typ := TypeOf(accPerson{})
+	a1, err := CachedAccessor(typ, "Home.City")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a2, _ := CachedAccessor(typ, "Home.City"); a2 != a1 {
+		t.Error("CachedAccessor() built a new Accessor for a cached path")
+	}
+	// The same text compiled as a literal field name is a different entry.
+	if _, err := CachedFieldAccessor(typ, "Home.City"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CachedFieldAccessor() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := CachedAccessor(typ, "Missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("CachedAccessor() error = %v, want %v", err, ErrNotFound)
+	}
+	if n := len(accessors.m); n != 1 {
+		t.Errorf("cache holds %d Accessors, want only the one that compiled", n)
+	}
+
+	ResetAccessorCache()
+	if a3, _ := CachedAccessor(typ, "Home.City"); a3 == a1 {
+		t.Error("CachedAccessor() returned a cached Accessor after ResetAccessorCache")
+	}
+
+	// Fill the cache up to its bound: the next new entry empties it first.
+	accessors.Lock()
+	for i := len(accessors.m); i < maxCachedAccessors; i++ {
+		accessors.m[accessorKey{path: strconv.Itoa(i)}] = a1
+	}
+	accessors.Unlock()
+	if _, err := CachedFieldAccessor(typ, "Name"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(accessors.m); n != 1 {
+		t.Errorf("cache holds %d Accessors after reaching its bound, want 1", n)
+	}
+	ResetAccessorCache()
+}
@@ -0,0 +1,170 @@
+package safereflect
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+)
+
+var jsonNumberType = reflect.TypeFor[json.Number]()
+
+// CallMethod calls the method of obj with the given name and returns its results. The method is
+// looked up on obj and, if obj is not a pointer, on a pointer to a copy of obj, so methods with
+// pointer receivers can be called too; their changes are then made to the copy.
+//
+// Arguments are adapted to the declared parameter types, which suits arguments that come from
+// configuration or JSON rather than from typed Go code:
+//
+//   - a nil argument becomes the zero value of a pointer, map, slice, interface, func or chan;
+//   - a json.Number or a string is parsed into a numeric or bool parameter;
+//   - other values are converted following the rules of Value.SetConvert, so a float64 holding a
+//     whole number can be passed for an int, but 1.5 can not.
+//
+// For variadic methods, the arguments beyond the fixed parameters are adapted to the element
+// type of the variadic parameter. When the last result of the method is an error, it is removed
+// from the results and returned as the error of CallMethod.
+func CallMethod(obj any, name string, args ...any) ([]any, error) {
+	const method = "safereflect.CallMethod"
+	v := ValueOf(obj)
+	m, err := v.MethodByName(name)
+	if err != nil && v.V.IsValid() && v.V.Kind() != reflect.Pointer {
+		p := reflect.New(v.V.Type())
+		p.Elem().Set(v.V)
+		m, err = Value{p}.MethodByName(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ft := m.Type()
+	numIn, _ := ft.NumIn()
+	variadic, _ := ft.IsVariadic()
+	fixed := numIn
+	if variadic {
+		fixed--
+	}
+	if len(args) < fixed || (!variadic && len(args) > fixed) {
+		want := strconv.Itoa(numIn) + " arguments"
+		if variadic {
+			want = "at least " + strconv.Itoa(fixed) + " arguments"
+		}
+		return nil, argError(method, want, strconv.Itoa(len(args)))
+	}
+	in := make([]Value, len(args))
+	for i, arg := range args {
+		var pt Type
+		if i < fixed {
+			pt, _ = ft.In(i)
+		} else {
+			last, _ := ft.In(numIn - 1)
+			pt = last.Elem()
+		}
+		a, err := adaptArg(arg, pt.ReflectType())
+		if err != nil {
+			err.Method = method
+			err.Index = i
+			return nil, err
+		}
+		in[i] = Value{a}
+	}
+
+	results, err := m.Call(in)
+	if err != nil {
+		return nil, err
+	}
+	numOut, _ := ft.NumOut()
+	if numOut > 0 {
+		if last, _ := ft.Out(numOut - 1); last.ReflectType() == errorType {
+			errResult := results[numOut-1].V
+			results = results[:numOut-1]
+			if !errResult.IsNil() {
+				return resultValues(results), errResult.Interface().(error)
+			}
+		}
+	}
+	return resultValues(results), nil
+}
+
+func resultValues(results []Value) []any {
+	out := make([]any, len(results))
+	for i, r := range results {
+		out[i] = r.V.Interface()
+	}
+	return out
+}
+
+// adaptArg converts arg to a value of type t as described by CallMethod.
+func adaptArg(arg any, t reflect.Type) (reflect.Value, *ValueError) {
+	if arg == nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, argError("", t.String(), "nil")
+	}
+	a := reflect.ValueOf(arg)
+	if a.Type().AssignableTo(t) {
+		return a, nil
+	}
+	if (a.Kind() == reflect.String || a.Type() == jsonNumberType) && (isNumericKind(t.Kind()) || t.Kind() == reflect.Bool) {
+		return parseArg(a.String(), t)
+	}
+	out := reflect.New(t).Elem()
+	if err := (Value{out}).SetConvert(Value{a}); err != nil {
+		e := err.(*ValueError)
+		return reflect.Value{}, e
+	}
+	return out, nil
+}
+
+// parseArg parses s into a value of the numeric or bool type t.
+func parseArg(s string, t reflect.Type) (reflect.Value, *ValueError) {
+	out := reflect.New(t).Elem()
+	var err error
+	switch t.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(s)
+		out.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		if n, err = strconv.ParseInt(s, 10, t.Bits()); err != nil {
+			// Numbers such as 1e3 or 2.0 are integers too, as long as nothing is truncated.
+			return parseFloatArg(s, t)
+		}
+		out.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		if n, err = strconv.ParseUint(s, 10, t.Bits()); err != nil {
+			return parseFloatArg(s, t)
+		}
+		out.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(s, t.Bits())
+		out.SetFloat(f)
+	case reflect.Complex64, reflect.Complex128:
+		var c complex128
+		c, err = strconv.ParseComplex(s, t.Bits())
+		out.SetComplex(c)
+	}
+	if err != nil {
+		return reflect.Value{}, argError("", t.String(), strconv.Quote(s))
+	}
+	return out, nil
+}
+
+func parseFloatArg(s string, t reflect.Type) (reflect.Value, *ValueError) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return reflect.Value{}, argError("", t.String(), strconv.Quote(s))
+	}
+	out, err := convertNumeric(reflect.ValueOf(f), t)
+	if err != nil {
+		e := newError("", err)
+		e.Expected = t.String()
+		e.Actual = strconv.Quote(s)
+		return reflect.Value{}, e
+	}
+	return out, nil
+}
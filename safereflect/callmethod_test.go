@@ -0,0 +1,121 @@
+package safereflect
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var errCalc = errors.New("calc")
+
+type calc struct {
+	Total int
+}
+
+func (calc) Add(a, b int) int                          { return a + b }
+func (calc) Scale(f float64, n int8) float64           { return f * float64(n) }
+func (calc) Enabled(b bool) bool                       { return b }
+func (calc) Join(sep string, parts ...string) string   { return strings.Join(parts, sep) }
+func (calc) Len(s []int, m map[string]int, p *int) int { return len(s) + len(m) }
+func (calc) Fail() error                               { return errCalc }
+func (calc) Nothing()                                  {}
+
+func (calc) Sum(ns ...int) int {
+	total := 0
+	for _, n := range ns {
+		total += n
+	}
+	return total
+}
+
+func (calc) Div(a, b int) (int, error) {
+	if b == 0 {
+		return 0, errCalc
+	}
+	return a / b, nil
+}
+
+func (c *calc) Inc(n uint) int {
+	c.Total += int(n)
+	return c.Total
+}
+
+func TestCallMethod(t *testing.T) {
+	tests := []struct {
+		name    string
+		obj     any
+		method  string
+		args    []any
+		want    []any
+		wantErr error
+	}{
+		{name: "typed arguments", obj: calc{}, method: "Add", args: []any{1, 2}, want: []any{3}},
+		{name: "JSON numbers", obj: calc{}, method: "Add", args: []any{json.Number("1"), json.Number("2e1")}, want: []any{21}},
+		{name: "float64 from JSON", obj: calc{}, method: "Add", args: []any{1.0, 2.0}, want: []any{3}},
+		{name: "float64 with fraction", obj: calc{}, method: "Add", args: []any{1.5, 2}, wantErr: ErrTruncated},
+		{name: "numeric strings", obj: calc{}, method: "Scale", args: []any{"1.5", "4"}, want: []any{6.0}},
+		{name: "string overflow", obj: calc{}, method: "Scale", args: []any{1.0, "300"}, wantErr: ErrOverflow},
+		{name: "string not a number", obj: calc{}, method: "Add", args: []any{"one", 2}, wantErr: ErrInvalidArgument},
+		{name: "bool string", obj: calc{}, method: "Enabled", args: []any{"true"}, want: []any{true}},
+		{name: "nil for reference types", obj: calc{}, method: "Len", args: []any{nil, nil, nil}, want: []any{0}},
+		{name: "nil for int", obj: calc{}, method: "Add", args: []any{nil, 1}, wantErr: ErrInvalidArgument},
+		{name: "variadic", obj: calc{}, method: "Sum", args: []any{1, 2.0, json.Number("3")}, want: []any{6}},
+		{name: "variadic without arguments", obj: calc{}, method: "Sum", want: []any{0}},
+		{name: "variadic after fixed", obj: calc{}, method: "Join", args: []any{"-", "a", "b"}, want: []any{"a-b"}},
+		{name: "variadic missing fixed", obj: calc{}, method: "Join", wantErr: ErrInvalidArgument},
+		{name: "too many arguments", obj: calc{}, method: "Add", args: []any{1, 2, 3}, wantErr: ErrInvalidArgument},
+		{name: "too few arguments", obj: calc{}, method: "Add", args: []any{1}, wantErr: ErrInvalidArgument},
+		{name: "trailing error removed", obj: calc{}, method: "Div", args: []any{6, 3}, want: []any{2}},
+		{name: "trailing error returned", obj: calc{}, method: "Div", args: []any{6, 0}, wantErr: errCalc},
+		{name: "only an error", obj: calc{}, method: "Fail", wantErr: errCalc},
+		{name: "no results", obj: calc{}, method: "Nothing", want: []any{}},
+		{name: "pointer receiver on a value", obj: calc{Total: 1}, method: "Inc", args: []any{2}, want: []any{3}},
+		{name: "negative for uint", obj: &calc{}, method: "Inc", args: []any{-1}, wantErr: ErrSignLoss},
+		{name: "unknown method", obj: calc{}, method: "Missing", wantErr: ErrNotFound},
+		{name: "unexported method", obj: calc{}, method: "add", wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CallMethod(tt.obj, tt.method, tt.args...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CallMethod() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallMethod() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CallMethod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallMethodArgumentIndex(t *testing.T) {
+	_, err := CallMethod(calc{}, "Add", 1, "x")
+	var ve *ValueError
+	if !errors.As(err, &ve) || ve.Index != 1 || ve.Method != "safereflect.CallMethod" {
+		t.Fatalf("CallMethod() error = %#v, want a *ValueError for argument 1", err)
+	}
+}
+
+func TestCallMethodReceivers(t *testing.T) {
+	c := calc{Total: 1}
+	if _, err := CallMethod(c, "Inc", 5); err != nil {
+		t.Fatal(err)
+	}
+	if c.Total != 1 {
+		t.Errorf("Total = %d, want the value passed in unchanged", c.Total)
+	}
+	p := &calc{Total: 1}
+	if _, err := CallMethod(p, "Inc", 5); err != nil {
+		t.Fatal(err)
+	}
+	if p.Total != 6 {
+		t.Errorf("Total = %d, want 6 through the pointer", p.Total)
+	}
+}
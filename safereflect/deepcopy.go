@@ -0,0 +1,174 @@
+package safereflect
+
+import "reflect"
+
+// An UnexportedPolicy tells DeepCopy what to do with unexported struct fields, which can not be
+// copied through reflection without bypassing the visibility rules.
+type UnexportedPolicy int
+
+const (
+	// UnexportedError fails the copy when an unexported field is not the zero value.
+	UnexportedError UnexportedPolicy = iota
+	// UnexportedZero leaves unexported fields out of the copy, at their zero value.
+	UnexportedZero
+	// UnexportedShallow copies unexported fields shallowly, so pointers, maps and slices held by
+	// them are shared with the original.
+	UnexportedShallow
+)
+
+// A CopyOption configures DeepCopy.
+type CopyOption func(*copyConfig)
+
+type copyConfig struct {
+	unexported UnexportedPolicy
+}
+
+// CopyUnexported selects how DeepCopy handles unexported struct fields. The default is
+// UnexportedError, so state that can not be copied is reported instead of silently dropped.
+// Values whose state is held in unexported fields, such as time.Time, are only copied faithfully
+// with UnexportedShallow.
+func CopyUnexported(policy UnexportedPolicy) CopyOption {
+	return func(c *copyConfig) {
+		c.unexported = policy
+	}
+}
+
+// DeepCopy returns a copy of v that shares no memory with it: pointers, maps, slices and the
+// values held by interfaces are copied recursively. Aliasing is preserved, so two pointers to
+// the same value in v point to the same copy, and cyclic values are copied as cycles. Slices
+// are treated as aliases when they share their start, length and capacity. Functions and
+// channels are shared rather than copied. A struct with a non-zero unexported field is an error
+// wrapping ErrUnexported unless opts select a different UnexportedPolicy.
+func DeepCopy(v any, opts ...CopyOption) (any, error) {
+	c := &copier{seen: make(map[copyVisit]reflect.Value)}
+	for _, opt := range opts {
+		opt(&c.cfg)
+	}
+	src := reflect.ValueOf(v)
+	if !src.IsValid() {
+		return nil, nil
+	}
+	dst := reflect.New(src.Type()).Elem()
+	if err := c.copy("", dst, src); err != nil {
+		return nil, err
+	}
+	return dst.Interface(), nil
+}
+
+type copyVisit struct {
+	ptr      uintptr
+	typ      reflect.Type
+	len, cap int
+}
+
+type copier struct {
+	cfg  copyConfig
+	seen map[copyVisit]reflect.Value
+}
+
+// copy stores a deep copy of src in dst, which is settable and holds either the zero value or,
+// with UnexportedShallow, a shallow copy of src. Paths are only tracked when they can end up in
+// an error.
+func (c *copier) copy(path string, dst, src reflect.Value) error {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return nil
+		}
+		key := copyVisit{ptr: src.Pointer(), typ: src.Type()}
+		if p, ok := c.seen[key]; ok {
+			dst.Set(p)
+			return nil
+		}
+		p := reflect.New(src.Type().Elem())
+		c.seen[key] = p
+		dst.Set(p)
+		return c.copy(path, p.Elem(), src.Elem())
+	case reflect.Interface:
+		if src.IsNil() {
+			return nil
+		}
+		e := reflect.New(src.Elem().Type()).Elem()
+		if err := c.copy(path, e, src.Elem()); err != nil {
+			return err
+		}
+		dst.Set(e)
+	case reflect.Map:
+		if src.IsNil() {
+			return nil
+		}
+		key := copyVisit{ptr: src.Pointer(), typ: src.Type()}
+		if m, ok := c.seen[key]; ok {
+			dst.Set(m)
+			return nil
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.seen[key] = m
+		dst.Set(m)
+		kt, et := src.Type().Key(), src.Type().Elem()
+		iter := src.MapRange()
+		for iter.Next() {
+			k, e := reflect.New(kt).Elem(), reflect.New(et).Elem()
+			if err := c.copy(path, k, iter.Key()); err != nil {
+				return err
+			}
+			if err := c.copy(c.childPath(path, func() string { return keyPath(path, iter.Key()) }), e, iter.Value()); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, e)
+		}
+	case reflect.Slice:
+		if src.IsNil() {
+			return nil
+		}
+		key := copyVisit{ptr: src.Pointer(), typ: src.Type(), len: src.Len(), cap: src.Cap()}
+		if s, ok := c.seen[key]; ok {
+			dst.Set(s)
+			return nil
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Cap())
+		c.seen[key] = s
+		dst.Set(s)
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(c.childPath(path, func() string { return indexPath(path, i) }), s.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			if err := c.copy(c.childPath(path, func() string { return indexPath(path, i) }), dst.Index(i), src.Index(i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Struct:
+		if c.cfg.unexported == UnexportedShallow {
+			dst.Set(src)
+		}
+		t := src.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fpath := c.childPath(path, func() string { return fieldPath(path, f.Name) })
+			if f.IsExported() {
+				if err := c.copy(fpath, dst.Field(i), src.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			if c.cfg.unexported == UnexportedError && !src.Field(i).IsZero() {
+				e := newError("safereflect.DeepCopy", ErrUnexported)
+				e.Actual = fpath
+				return e
+			}
+		}
+	default:
+		dst.Set(src)
+	}
+	return nil
+}
+
+func (c *copier) childPath(path string, child func() string) string {
+	if c.cfg.unexported != UnexportedError {
+		return path
+	}
+	return child()
+}
@@ -0,0 +1,223 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+type copyNode struct {
+	Name     string
+	Children []*copyNode
+	Parent   *copyNode
+	Attrs    map[string]any
+	Data     [2][]int
+	secret   *int
+}
+
+func TestDeepCopy(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+	}{
+		{name: "nil", v: nil},
+		{name: "int", v: 1},
+		{name: "string", v: "s"},
+		{name: "slice", v: []int{1, 2, 3}},
+		{name: "nil slice", v: []int(nil)},
+		{name: "map of slices", v: map[string][]string{"a": {"x"}}},
+		{name: "pointer", v: &copyNode{Name: "n"}},
+		{name: "array of slices", v: [2][]int{{1}, {2}}},
+		{name: "interface values", v: []any{1, "a", []int{2}, map[string]any{"k": 1.5}}},
+		{name: "struct", v: copyNode{Name: "n", Attrs: map[string]any{"a": []int{1}}, Data: [2][]int{{1}, nil}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeepCopy(tt.v)
+			if err != nil {
+				t.Fatalf("DeepCopy() error = %v", err)
+			}
+			if !DeepEqual(got, tt.v) {
+				t.Errorf("DeepCopy() = %#v, want %#v", got, tt.v)
+			}
+		})
+	}
+}
+
+func TestDeepCopySharesNothing(t *testing.T) {
+	src := &copyNode{
+		Name:     "root",
+		Children: []*copyNode{{Name: "c"}},
+		Attrs:    map[string]any{"list": []int{1}, "map": map[string]int{"a": 1}},
+		Data:     [2][]int{{1}, {2}},
+	}
+	out, err := DeepCopy(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := out.(*copyNode)
+	dst.Children[0].Name = "changed"
+	dst.Attrs["list"].([]int)[0] = 9
+	dst.Attrs["map"].(map[string]int)["a"] = 9
+	dst.Data[0][0] = 9
+	want := &copyNode{
+		Name:     "root",
+		Children: []*copyNode{{Name: "c"}},
+		Attrs:    map[string]any{"list": []int{1}, "map": map[string]int{"a": 1}},
+		Data:     [2][]int{{1}, {2}},
+	}
+	if d := Diff(src, want); d != nil {
+		t.Errorf("modifying the copy changed the original: %v", d)
+	}
+}
+
+func TestDeepCopyAliasingAndCycles(t *testing.T) {
+	root := &copyNode{Name: "root"}
+	child := &copyNode{Name: "child", Parent: root}
+	root.Children = []*copyNode{child, child}
+	root.Parent = root
+	shared := []int{1, 2}
+	root.Data = [2][]int{shared, shared}
+
+	out, err := DeepCopy(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := out.(*copyNode)
+	switch {
+	case c == root:
+		t.Fatal("DeepCopy() returned the original pointer")
+	case c.Parent != c:
+		t.Error("self reference is not preserved")
+	case c.Children[0] != c.Children[1]:
+		t.Error("two pointers to one value point to different copies")
+	case c.Children[0] == child:
+		t.Error("child is shared with the original")
+	case c.Children[0].Parent != c:
+		t.Error("cycle through the child is not preserved")
+	}
+	c.Data[0][0] = 9
+	if c.Data[1][0] != 9 {
+		t.Error("slices sharing their backing array are not aliased in the copy")
+	}
+	if shared[0] != 1 {
+		t.Error("the copied slice shares memory with the original")
+	}
+
+	// A subslice is a different slice, so it gets its own copy.
+	s := []int{1, 2, 3}
+	out, _ = DeepCopy([][]int{s, s[:2]})
+	parts := out.([][]int)
+	parts[0][0] = 9
+	if parts[1][0] != 1 {
+		t.Error("slices of different length are aliased in the copy")
+	}
+}
+
+func TestDeepCopyUnexported(t *testing.T) {
+	n := 1
+	src := copyNode{Name: "n", secret: &n}
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		v       any
+		opts    []CopyOption
+		check   func(t *testing.T, got any)
+		wantErr error
+	}{
+		{
+			name:    "error by default",
+			v:       []copyNode{{}, src},
+			wantErr: ErrUnexported,
+		},
+		{
+			name:    "time.Time fails by default",
+			v:       now,
+			wantErr: ErrUnexported,
+		},
+		{
+			name: "zero",
+			v:    src,
+			opts: []CopyOption{CopyUnexported(UnexportedZero)},
+			check: func(t *testing.T, got any) {
+				if c := got.(copyNode); c.secret != nil || c.Name != "n" {
+					t.Errorf("copy = %+v, want the unexported field left out", c)
+				}
+			},
+		},
+		{
+			name: "shallow",
+			v:    src,
+			opts: []CopyOption{CopyUnexported(UnexportedShallow)},
+			check: func(t *testing.T, got any) {
+				if c := got.(copyNode); c.secret != &n {
+					t.Errorf("secret = %p, want the original pointer %p", c.secret, &n)
+				}
+			},
+		},
+		{
+			name: "shallow keeps time.Time",
+			v:    now,
+			opts: []CopyOption{CopyUnexported(UnexportedShallow)},
+			check: func(t *testing.T, got any) {
+				if !got.(time.Time).Equal(now) {
+					t.Errorf("copy = %v, want %v", got, now)
+				}
+			},
+		},
+		{
+			name:    "error",
+			v:       []copyNode{{}, src},
+			opts:    []CopyOption{CopyUnexported(UnexportedError)},
+			wantErr: ErrUnexported,
+		},
+		{
+			name: "error accepts zero unexported fields",
+			v:    copyNode{Name: "n"},
+			opts: []CopyOption{CopyUnexported(UnexportedError)},
+			check: func(t *testing.T, got any) {
+				if got.(copyNode).Name != "n" {
+					t.Errorf("copy = %+v", got)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeepCopy(tt.v, tt.opts...)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("DeepCopy() error = %v, want %v", err, tt.wantErr)
+				}
+				var ve *ValueError
+				if _, ok := tt.v.([]copyNode); ok && errors.As(err, &ve) && ve.Actual != "[1].secret" {
+					t.Errorf("error path = %q, want [1].secret", ve.Actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DeepCopy() error = %v", err)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestDeepCopySharesFuncsAndChans(t *testing.T) {
+	ch := make(chan int)
+	out, err := DeepCopy(struct {
+		C chan int
+		F func() int
+	}{C: ch, F: func() int { return 1 }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.(struct {
+		C chan int
+		F func() int
+	})
+	if got.C != ch || got.F() != 1 {
+		t.Errorf("copy = %+v, want the same channel and function", got)
+	}
+}
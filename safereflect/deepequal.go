@@ -0,0 +1,305 @@
+package safereflect
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// A Difference is a single difference found by Diff. Path locates it from the root, for example
+// Items[3].Address.City, and A and B hold the values found there. A or B is the zero Value when
+// the element only exists on the other side, such as a missing map key or a shorter slice.
+type Difference struct {
+	Path string
+	A, B Value
+}
+
+func (d Difference) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(root)"
+	}
+	return fmt.Sprintf("%s: %s != %s", path, formatDiffValue(d.A), formatDiffValue(d.B))
+}
+
+func formatDiffValue(v Value) string {
+	if !v.V.IsValid() {
+		return "<missing>"
+	}
+	return fmt.Sprintf("%#v", v.V)
+}
+
+// A DiffOption configures DeepEqual and Diff.
+type DiffOption func(*diffConfig)
+
+type diffConfig struct {
+	ignoreNames    map[string]bool
+	ignoreTags     []tagMatch
+	floatTolerance float64
+	nilEqualsEmpty bool
+}
+
+type tagMatch struct {
+	key, value string
+}
+
+// IgnoreFields skips struct fields with one of the given names. A name can either be a bare
+// field name, which matches that field in every struct, or a full path such as Address.City.
+func IgnoreFields(names ...string) DiffOption {
+	return func(c *diffConfig) {
+		for _, n := range names {
+			c.ignoreNames[n] = true
+		}
+	}
+}
+
+// IgnoreTag skips struct fields whose tag has the given key with the given value, for example
+// IgnoreTag("diff", "-"). An empty value skips every field that has the key at all.
+func IgnoreTag(key, value string) DiffOption {
+	return func(c *diffConfig) {
+		c.ignoreTags = append(c.ignoreTags, tagMatch{key, value})
+	}
+}
+
+// FloatTolerance treats floats, and the parts of complex numbers, as equal when they differ by
+// at most epsilon.
+func FloatTolerance(epsilon float64) DiffOption {
+	return func(c *diffConfig) {
+		c.floatTolerance = math.Abs(epsilon)
+	}
+}
+
+// NilEqualsEmpty treats a nil slice or map as equal to an empty one.
+func NilEqualsEmpty() DiffOption {
+	return func(c *diffConfig) {
+		c.nilEqualsEmpty = true
+	}
+}
+
+// DeepEqual reports whether a and b are deeply equal, using the same rules as reflect.DeepEqual
+// adjusted by opts. Cyclic values are handled. Use Diff to find out where two values differ.
+func DeepEqual(a, b any, opts ...DiffOption) bool {
+	return len(diff(a, b, 1, opts)) == 0
+}
+
+// Diff returns every difference between a and b, in traversal order, so the first element is
+// the path to the first difference. Map keys are visited in sorted order so the result is
+// deterministic. Diff returns nil when DeepEqual would report true.
+func Diff(a, b any, opts ...DiffOption) []Difference {
+	return diff(a, b, -1, opts)
+}
+
+func diff(a, b any, limit int, opts []DiffOption) []Difference {
+	d := &differ{
+		cfg:     diffConfig{ignoreNames: make(map[string]bool)},
+		visited: make(map[diffVisit]bool),
+		limit:   limit,
+	}
+	for _, opt := range opts {
+		opt(&d.cfg)
+	}
+	d.compare("", reflect.ValueOf(a), reflect.ValueOf(b))
+	return d.diffs
+}
+
+type diffVisit struct {
+	a, b       uintptr
+	typ        reflect.Type
+	aLen, bLen int
+}
+
+type differ struct {
+	cfg     diffConfig
+	visited map[diffVisit]bool
+	diffs   []Difference
+	limit   int
+}
+
+func (d *differ) done() bool {
+	return d.limit >= 0 && len(d.diffs) >= d.limit
+}
+
+func (d *differ) report(path string, a, b reflect.Value) {
+	d.diffs = append(d.diffs, Difference{Path: path, A: Value{a}, B: Value{b}})
+}
+
+func (d *differ) compare(path string, a, b reflect.Value) {
+	if d.done() {
+		return
+	}
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			d.report(path, a, b)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		d.report(path, a, b)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface:
+		if !a.IsNil() && !b.IsNil() && a.Kind() != reflect.Interface {
+			v := diffVisit{a: a.Pointer(), b: b.Pointer(), typ: a.Type()}
+			if a.Kind() == reflect.Slice {
+				// Subslices of one array share their data pointer, so only the lengths tell them apart.
+				v.aLen, v.bLen = a.Len(), b.Len()
+			}
+			if d.visited[v] {
+				return
+			}
+			d.visited[v] = true
+		}
+	}
+
+	switch a.Kind() {
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			d.compare(indexPath(path, i), a.Index(i), b.Index(i))
+		}
+	case reflect.Slice:
+		if a.IsNil() != b.IsNil() && !(d.cfg.nilEqualsEmpty && a.Len() == 0 && b.Len() == 0) {
+			d.report(path, a, b)
+			return
+		}
+		for i := 0; i < max(a.Len(), b.Len()) && !d.done(); i++ {
+			if i >= a.Len() {
+				d.report(indexPath(path, i), reflect.Value{}, b.Index(i))
+			} else if i >= b.Len() {
+				d.report(indexPath(path, i), a.Index(i), reflect.Value{})
+			} else {
+				d.compare(indexPath(path, i), a.Index(i), b.Index(i))
+			}
+		}
+	case reflect.Interface, reflect.Pointer:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				d.report(path, a, b)
+			}
+			return
+		}
+		if a.Kind() == reflect.Pointer && a.Pointer() == b.Pointer() {
+			return
+		}
+		d.compare(path, a.Elem(), b.Elem())
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			fpath := fieldPath(path, f.Name)
+			if d.ignored(f, fpath) {
+				continue
+			}
+			d.compare(fpath, a.Field(i), b.Field(i))
+		}
+	case reflect.Map:
+		if a.IsNil() != b.IsNil() && !(d.cfg.nilEqualsEmpty && a.Len() == 0 && b.Len() == 0) {
+			d.report(path, a, b)
+			return
+		}
+		for _, k := range sortedKeys(a, b) {
+			if d.done() {
+				return
+			}
+			av, bv := a.MapIndex(k), b.MapIndex(k)
+			if !av.IsValid() || !bv.IsValid() {
+				d.report(keyPath(path, k), av, bv)
+				continue
+			}
+			d.compare(keyPath(path, k), av, bv)
+		}
+	case reflect.Func:
+		if !a.IsNil() || !b.IsNil() {
+			d.report(path, a, b)
+		}
+	case reflect.Float32, reflect.Float64:
+		if !d.floatsEqual(a.Float(), b.Float()) {
+			d.report(path, a, b)
+		}
+	case reflect.Complex64, reflect.Complex128:
+		ac, bc := a.Complex(), b.Complex()
+		if !d.floatsEqual(real(ac), real(bc)) || !d.floatsEqual(imag(ac), imag(bc)) {
+			d.report(path, a, b)
+		}
+	case reflect.Bool:
+		if a.Bool() != b.Bool() {
+			d.report(path, a, b)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if a.Int() != b.Int() {
+			d.report(path, a, b)
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if a.Uint() != b.Uint() {
+			d.report(path, a, b)
+		}
+	case reflect.String:
+		if a.String() != b.String() {
+			d.report(path, a, b)
+		}
+	case reflect.Chan, reflect.UnsafePointer:
+		if a.Pointer() != b.Pointer() {
+			d.report(path, a, b)
+		}
+	}
+}
+
+func (d *differ) floatsEqual(x, y float64) bool {
+	if x == y {
+		return true
+	}
+	return math.Abs(x-y) <= d.cfg.floatTolerance
+}
+
+func (d *differ) ignored(f reflect.StructField, path string) bool {
+	if d.cfg.ignoreNames[f.Name] || d.cfg.ignoreNames[path] {
+		return true
+	}
+	return tagMatches(f.Tag, d.cfg.ignoreTags)
+}
+
+func tagMatches(tag reflect.StructTag, matches []tagMatch) bool {
+	for _, m := range matches {
+		if v, ok := tag.Lookup(m.key); ok && (m.value == "" || v == m.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// sortedKeys returns the union of the keys of the maps a and b, sorted by their formatted value.
+func sortedKeys(a, b reflect.Value) []reflect.Value {
+	keys := a.MapKeys()
+	for _, k := range b.MapKeys() {
+		if !a.MapIndex(k).IsValid() {
+			keys = append(keys, k)
+		}
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+// fieldPath, indexPath and keyPath build the paths used throughout this package, in the form
+// Orders[2].Items["sku"].Price, which Value.Lookup and Value.SetPath accept in turn.
+func fieldPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func indexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+func keyPath(path string, key reflect.Value) string {
+	if key.Kind() == reflect.String {
+		return path + "[" + strconv.Quote(key.String()) + "]"
+	}
+	return path + "[" + fmt.Sprint(key) + "]"
+}
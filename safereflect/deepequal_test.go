@@ -0,0 +1,137 @@
+package safereflect
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+type diffAddress struct {
+	City string
+	Zip  string `diff:"-"`
+}
+
+type diffPerson struct {
+	Name    string
+	Address diffAddress
+	Tags    []string
+	Scores  map[string]float64
+	Next    *diffPerson
+}
+
+func TestDiff(t *testing.T) {
+	base := func() diffPerson {
+		return diffPerson{
+			Name:    "a",
+			Address: diffAddress{City: "x", Zip: "1"},
+			Tags:    []string{"t1", "t2"},
+			Scores:  map[string]float64{"m": 1, "n": 2},
+		}
+	}
+	with := func(f func(*diffPerson)) diffPerson {
+		p := base()
+		f(&p)
+		return p
+	}
+
+	tests := []struct {
+		name  string
+		a, b  any
+		opts  []DiffOption
+		paths []string
+	}{
+		{name: "equal", a: base(), b: base()},
+		{name: "top-level field", a: base(), b: with(func(p *diffPerson) { p.Name = "b" }), paths: []string{"Name"}},
+		{name: "nested field", a: base(), b: with(func(p *diffPerson) { p.Address.City = "y" }), paths: []string{"Address.City"}},
+		{name: "slice element", a: base(), b: with(func(p *diffPerson) { p.Tags[1] = "t3" }), paths: []string{"Tags[1]"}},
+		{name: "longer slice", a: base(), b: with(func(p *diffPerson) { p.Tags = append(p.Tags, "t3") }), paths: []string{"Tags[2]"}},
+		{name: "map value", a: base(), b: with(func(p *diffPerson) { p.Scores["n"] = 3 }), paths: []string{`Scores["n"]`}},
+		{
+			name:  "map keys in sorted order",
+			a:     base(),
+			b:     with(func(p *diffPerson) { delete(p.Scores, "m"); p.Scores["z"] = 1 }),
+			paths: []string{`Scores["m"]`, `Scores["z"]`},
+		},
+		{name: "pointer target", a: with(func(p *diffPerson) { p.Next = &diffPerson{Name: "c"} }), b: with(func(p *diffPerson) { p.Next = &diffPerson{Name: "d"} }), paths: []string{"Next.Name"}},
+		{name: "nil pointer", a: base(), b: with(func(p *diffPerson) { p.Next = &diffPerson{} }), paths: []string{"Next"}},
+		{name: "several differences", a: base(), b: with(func(p *diffPerson) { p.Name = "b"; p.Tags[0] = "t0" }), paths: []string{"Name", "Tags[0]"}},
+		{name: "different root types", a: 1, b: int64(1), paths: []string{""}},
+		{name: "int map keys", a: map[int]int{1: 1, 2: 2}, b: map[int]int{1: 1, 2: 3}, paths: []string{"[2]"}},
+		{name: "nil vs empty slice", a: []int(nil), b: []int{}, paths: []string{""}},
+		{name: "nil vs empty slice allowed", a: []int(nil), b: []int{}, opts: []DiffOption{NilEqualsEmpty()}},
+		{name: "nil vs empty map allowed", a: map[string]int(nil), b: map[string]int{}, opts: []DiffOption{NilEqualsEmpty()}},
+		{name: "ignored bare name", a: base(), b: with(func(p *diffPerson) { p.Address.City = "y" }), opts: []DiffOption{IgnoreFields("City")}},
+		{name: "ignored path", a: base(), b: with(func(p *diffPerson) { p.Address.City = "y" }), opts: []DiffOption{IgnoreFields("Address.City")}},
+		{name: "other path not ignored", a: base(), b: with(func(p *diffPerson) { p.Address.City = "y" }), opts: []DiffOption{IgnoreFields("Next.City")}, paths: []string{"Address.City"}},
+		{name: "tag not ignored by default", a: base(), b: with(func(p *diffPerson) { p.Address.Zip = "2" }), paths: []string{"Address.Zip"}},
+		{name: "ignored tag", a: base(), b: with(func(p *diffPerson) { p.Address.Zip = "2" }), opts: []DiffOption{IgnoreTag("diff", "-")}},
+		{name: "ignored tag key", a: base(), b: with(func(p *diffPerson) { p.Address.Zip = "2" }), opts: []DiffOption{IgnoreTag("diff", "")}},
+		{name: "float difference", a: 1.0, b: 1.0001, paths: []string{""}},
+		{name: "float within tolerance", a: 1.0, b: 1.0001, opts: []DiffOption{FloatTolerance(0.001)}},
+		{name: "complex within tolerance", a: 1 + 1i, b: 1.0001 + 1i, opts: []DiffOption{FloatTolerance(-0.001)}},
+		{name: "NaN", a: math.NaN(), b: math.NaN(), paths: []string{""}},
+		{name: "non-nil funcs", a: func() {}, b: func() {}, paths: []string{""}},
+		{name: "nil funcs", a: (func())(nil), b: (func())(nil)},
+		{name: "untyped nil", a: nil, b: nil},
+		{name: "nil vs value", a: nil, b: 1, paths: []string{""}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			for _, d := range Diff(tt.a, tt.b, tt.opts...) {
+				paths = append(paths, d.Path)
+			}
+			if !reflect.DeepEqual(paths, tt.paths) {
+				t.Errorf("Diff() paths = %q, want %q", paths, tt.paths)
+			}
+			if got := DeepEqual(tt.a, tt.b, tt.opts...); got != (len(tt.paths) == 0) {
+				t.Errorf("DeepEqual() = %v, want %v", got, len(tt.paths) == 0)
+			}
+		})
+	}
+}
+
+func TestDiffCycles(t *testing.T) {
+	a := &diffPerson{Name: "a"}
+	a.Next = a
+	b := &diffPerson{Name: "a"}
+	b.Next = b
+	if !DeepEqual(a, b) {
+		t.Error("DeepEqual() = false for equal cyclic values")
+	}
+	b.Name = "b"
+	if d := Diff(a, b); len(d) != 1 || d[0].Path != "Name" {
+		t.Errorf("Diff() = %v, want a single difference at Name", d)
+	}
+}
+
+func TestDiffSubslices(t *testing.T) {
+	type pair struct{ A, B []int }
+	s := []int{1, 2}
+	a := pair{s[:1], s[:2]}
+	b := pair{s[:1], s[:1]}
+	if DeepEqual(a, b) {
+		t.Error("DeepEqual() = true for subslices of one array with different lengths")
+	}
+	if d := Diff(a, b); len(d) != 1 || d[0].Path != "B[1]" {
+		t.Errorf("Diff() = %v, want a single difference at B[1]", d)
+	}
+}
+
+func TestDifferenceString(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Difference
+		want string
+	}{
+		{name: "root", d: Difference{A: ValueOf(1), B: ValueOf(2)}, want: "(root): 1 != 2"},
+		{name: "missing", d: Difference{Path: `M["k"]`, A: ValueOf("v")}, want: `M["k"]: "v" != <missing>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
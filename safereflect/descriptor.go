@@ -0,0 +1,289 @@
+package safereflect
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// A TypeDescriptor is a portable description of a type, meant to be stored as JSON and turned
+// back into a Type with TypeFromDescriptor, possibly by another process. Kind is the name of
+// the kind as returned by Kind.String, and the other fields are set as the kind requires:
+// Elem for arrays, channels, maps, pointers and slices, Key for maps, Len for arrays, Dir for
+// channels, Fields for structs, and In, Out and Variadic for functions. Named types are only
+// described by their Name, which is resolved through a Registry.
+type TypeDescriptor struct {
+	Kind     string            `json:"kind"`
+	Name     string            `json:"name,omitempty"`
+	Elem     *TypeDescriptor   `json:"elem,omitempty"`
+	Key      *TypeDescriptor   `json:"key,omitempty"`
+	Len      int               `json:"len,omitempty"`
+	Dir      string            `json:"dir,omitempty"`
+	Fields   []FieldDescriptor `json:"fields,omitempty"`
+	In       []*TypeDescriptor `json:"in,omitempty"`
+	Out      []*TypeDescriptor `json:"out,omitempty"`
+	Variadic bool              `json:"variadic,omitempty"`
+}
+
+// A FieldDescriptor describes a struct field in a TypeDescriptor.
+type FieldDescriptor struct {
+	Name     string          `json:"name"`
+	Type     *TypeDescriptor `json:"type"`
+	Tag      string          `json:"tag,omitempty"`
+	Embedded bool            `json:"embedded,omitempty"`
+}
+
+// predeclared holds the predeclared types, which are known without a Registry.
+var predeclared = map[string]reflect.Type{
+	"bool":       reflect.TypeFor[bool](),
+	"int":        reflect.TypeFor[int](),
+	"int8":       reflect.TypeFor[int8](),
+	"int16":      reflect.TypeFor[int16](),
+	"int32":      reflect.TypeFor[int32](),
+	"int64":      reflect.TypeFor[int64](),
+	"uint":       reflect.TypeFor[uint](),
+	"uint8":      reflect.TypeFor[uint8](),
+	"uint16":     reflect.TypeFor[uint16](),
+	"uint32":     reflect.TypeFor[uint32](),
+	"uint64":     reflect.TypeFor[uint64](),
+	"uintptr":    reflect.TypeFor[uintptr](),
+	"float32":    reflect.TypeFor[float32](),
+	"float64":    reflect.TypeFor[float64](),
+	"complex64":  reflect.TypeFor[complex64](),
+	"complex128": reflect.TypeFor[complex128](),
+	"string":     reflect.TypeFor[string](),
+	"error":      errorType,
+}
+
+var chanDirNames = map[ChanDir]string{
+	BothDir: "both",
+	RecvDir: "recv",
+	SendDir: "send",
+}
+
+// DescribeType returns a descriptor for t. Types made of the predeclared types, arrays,
+// channels, functions, maps, pointers, slices, structs and the empty interface are described
+// by their structure. A named type, other than a predeclared one, and any other type found in
+// registry, is described by the name it was registered under; the registry may be nil when t
+// refers to no such types. Struct fields must be exported, as StructOf can not rebuild
+// unexported ones.
+func DescribeType(t Type, registry *Registry) (*TypeDescriptor, error) {
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError("safereflect.DescribeType", ErrNilType)
+	}
+	return describeType(t.ReflectType(), registry)
+}
+
+func describeType(t reflect.Type, registry *Registry) (*TypeDescriptor, error) {
+	const method = "safereflect.DescribeType"
+	d := &TypeDescriptor{Kind: Kind(t.Kind()).String()}
+	if name, ok := registry.NameOf(toType(t)); ok {
+		d.Name = name
+		return d, nil
+	}
+	if t.Name() != "" {
+		if predeclared[t.Name()] == t {
+			if t == errorType {
+				d.Name = "error"
+			}
+			return d, nil
+		}
+		e := newError(method, ErrNotFound)
+		e.Expected = "registered named type"
+		e.Actual = t.String()
+		return nil, e
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.Array, reflect.Chan, reflect.Pointer, reflect.Slice:
+		if d.Elem, err = describeType(t.Elem(), registry); err != nil {
+			return nil, err
+		}
+		if t.Kind() == reflect.Array {
+			d.Len = t.Len()
+		}
+		if t.Kind() == reflect.Chan {
+			d.Dir = chanDirNames[ChanDir(t.ChanDir())]
+		}
+	case reflect.Map:
+		if d.Key, err = describeType(t.Key(), registry); err != nil {
+			return nil, err
+		}
+		if d.Elem, err = describeType(t.Elem(), registry); err != nil {
+			return nil, err
+		}
+	case reflect.Struct:
+		d.Fields = make([]FieldDescriptor, t.NumField())
+		for i := range d.Fields {
+			f := t.Field(i)
+			if !f.IsExported() {
+				e := newError(method, ErrUnexported)
+				e.Actual = "field " + f.Name + " of " + t.String()
+				return nil, e
+			}
+			ft, err := describeType(f.Type, registry)
+			if err != nil {
+				return nil, err
+			}
+			d.Fields[i] = FieldDescriptor{Name: f.Name, Type: ft, Tag: string(f.Tag), Embedded: f.Anonymous}
+		}
+	case reflect.Func:
+		d.Variadic = t.IsVariadic()
+		for i := 0; i < t.NumIn(); i++ {
+			in, err := describeType(t.In(i), registry)
+			if err != nil {
+				return nil, err
+			}
+			d.In = append(d.In, in)
+		}
+		for i := 0; i < t.NumOut(); i++ {
+			out, err := describeType(t.Out(i), registry)
+			if err != nil {
+				return nil, err
+			}
+			d.Out = append(d.Out, out)
+		}
+	case reflect.Interface:
+		if t.NumMethod() > 0 {
+			e := newError(method, ErrNotFound)
+			e.Expected = "empty or registered interface"
+			e.Actual = t.String()
+			return nil, e
+		}
+	default:
+		return nil, kindError(method, "describable kind", t.Kind())
+	}
+	return d, nil
+}
+
+// TypeFromDescriptor builds the type described by d, resolving names through registry, which
+// may be nil when d names no types. Types are rebuilt with StructOf, SliceOf, MapOf and the
+// other constructors, so a descriptor returned by DescribeType yields a type identical to the
+// one it describes.
+func TypeFromDescriptor(d *TypeDescriptor, registry *Registry) (Type, error) {
+	t, err := typeFromDescriptor(d, registry)
+	if err != nil {
+		return nil, err
+	}
+	return toType(t), nil
+}
+
+func typeFromDescriptor(d *TypeDescriptor, registry *Registry) (reflect.Type, error) {
+	const method = "safereflect.TypeFromDescriptor"
+	if d == nil {
+		return nil, newError(method, ErrNilType)
+	}
+	if d.Name != "" {
+		var t reflect.Type
+		if rt, ok := registry.Lookup(d.Name); ok {
+			t = rt.ReflectType()
+		} else if pt, ok := predeclared[d.Name]; ok {
+			t = pt
+		} else {
+			e := newError(method, ErrNotFound)
+			e.Expected = "registered type"
+			e.Actual = strconv.Quote(d.Name)
+			return nil, e
+		}
+		if Kind(t.Kind()).String() != d.Kind {
+			return nil, kindError(method, d.Kind, t.Kind())
+		}
+		return t, nil
+	}
+	if t, ok := predeclared[d.Kind]; ok && d.Kind != "error" {
+		return t, nil
+	}
+
+	switch d.Kind {
+	case "array", "chan", "ptr", "slice":
+		elem, err := typeFromDescriptor(d.Elem, registry)
+		if err != nil {
+			return nil, err
+		}
+		var t Type
+		switch d.Kind {
+		case "array":
+			t, err = ArrayOf(d.Len, toType(elem))
+		case "chan":
+			dir, ok := chanDirOf(d.Dir)
+			if !ok {
+				return nil, argError(method, "both, recv or send", strconv.Quote(d.Dir))
+			}
+			t, err = ChanOf(dir, toType(elem))
+		case "ptr":
+			t, err = PointerTo(toType(elem))
+		default:
+			t = SliceOf(toType(elem))
+		}
+		if err != nil {
+			return nil, err
+		}
+		return t.ReflectType(), nil
+	case "map":
+		key, err := typeFromDescriptor(d.Key, registry)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := typeFromDescriptor(d.Elem, registry)
+		if err != nil {
+			return nil, err
+		}
+		t, err := MapOf(toType(key), toType(elem))
+		if err != nil {
+			return nil, err
+		}
+		return t.ReflectType(), nil
+	case "struct":
+		fields := make([]StructField, len(d.Fields))
+		for i, f := range d.Fields {
+			ft, err := typeFromDescriptor(f.Type, registry)
+			if err != nil {
+				return nil, err
+			}
+			fields[i] = StructField{Name: f.Name, Type: toType(ft), Tag: StructTag(f.Tag), Anonymous: f.Embedded}
+		}
+		t, err := StructOf(fields)
+		if err != nil {
+			return nil, err
+		}
+		return t.ReflectType(), nil
+	case "func":
+		in := make([]Type, len(d.In))
+		for i, p := range d.In {
+			t, err := typeFromDescriptor(p, registry)
+			if err != nil {
+				return nil, err
+			}
+			in[i] = toType(t)
+		}
+		out := make([]Type, len(d.Out))
+		for i, p := range d.Out {
+			t, err := typeFromDescriptor(p, registry)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = toType(t)
+		}
+		t, err := FuncOf(in, out, d.Variadic)
+		if err != nil {
+			return nil, err
+		}
+		return t.ReflectType(), nil
+	case "interface":
+		return reflect.TypeFor[any](), nil
+	}
+	return nil, argError(method, "known kind", strconv.Quote(d.Kind))
+}
+
+// chanDirOf returns the direction named by dir, which defaults to BothDir.
+func chanDirOf(dir string) (ChanDir, bool) {
+	if dir == "" {
+		return BothDir, true
+	}
+	for d, name := range chanDirNames {
+		if name == dir {
+			return d, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,146 @@
+package safereflect
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type descOrder struct {
+	ID int
+}
+
+func TestDescriptorRoundTrip(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("time.Time", TypeOf(time.Time{})); err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("io.Reader", TypeFor[io.Reader]()); err != nil {
+		t.Fatal(err)
+	}
+	embedded, err := StructOf([]StructField{{Name: "Marker", Type: TypeOf(Marker{}), Anonymous: true}, {Name: "N", Type: TypeOf(0)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := registry.Register("Marker", TypeOf(Marker{})); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		typ  Type
+	}{
+		{name: "predeclared", typ: TypeOf(uint16(0))},
+		{name: "error", typ: TypeFor[error]()},
+		{name: "empty interface", typ: TypeFor[any]()},
+		{name: "array", typ: TypeOf([3]byte{})},
+		{name: "receive channel", typ: TypeFor[<-chan int]()},
+		{name: "send channel", typ: TypeFor[chan<- string]()},
+		{name: "pointer to slice", typ: TypeOf(&[]int{})},
+		{name: "map", typ: TypeOf(map[string][]float32{})},
+		{name: "func", typ: TypeOf(func(int, ...string) (bool, error) { return false, nil })},
+		{name: "registered named type", typ: TypeOf(time.Time{})},
+		{name: "registered interface", typ: TypeOf([]io.Reader{})},
+		{name: "anonymous struct with tags", typ: TypeOf(struct {
+			A int    `json:"a"`
+			B string `json:"b,omitempty" yaml:"b"`
+		}{})},
+		{name: "embedded field", typ: embedded},
+		{name: "nested", typ: TypeOf(map[string]struct {
+			Items []struct{ At *time.Time }
+		}{})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := DescribeType(tt.typ, registry)
+			if err != nil {
+				t.Fatalf("DescribeType() error = %v", err)
+			}
+			data, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var decoded TypeDescriptor
+			if err := json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			got, err := TypeFromDescriptor(&decoded, registry)
+			if err != nil {
+				t.Fatalf("TypeFromDescriptor(%s) error = %v", data, err)
+			}
+			if got != tt.typ {
+				t.Errorf("TypeFromDescriptor(%s) = %v, want the identical type %v", data, got, tt.typ)
+			}
+		})
+	}
+}
+
+func TestDescribeType(t *testing.T) {
+	d, err := DescribeType(TypeOf(map[string]*[2]int{}), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &TypeDescriptor{
+		Kind: "map",
+		Key:  &TypeDescriptor{Kind: "string"},
+		Elem: &TypeDescriptor{Kind: "ptr", Elem: &TypeDescriptor{Kind: "array", Len: 2, Elem: &TypeDescriptor{Kind: "int"}}},
+	}
+	if !reflect.DeepEqual(d, want) {
+		got, _ := json.Marshal(d)
+		t.Errorf("DescribeType() = %s", got)
+	}
+}
+
+func TestDescribeTypeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     Type
+		wantErr error
+	}{
+		{name: "nil type", typ: nil, wantErr: ErrNilType},
+		{name: "unregistered named type", typ: TypeOf(descOrder{}), wantErr: ErrNotFound},
+		{name: "unregistered named type inside", typ: TypeOf([]time.Duration{}), wantErr: ErrNotFound},
+		{name: "unexported field", typ: TypeOf(struct{ a int }{}), wantErr: ErrUnexported},
+		{name: "non-empty interface", typ: TypeFor[io.Writer](), wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DescribeType(tt.typ, nil); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DescribeType() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTypeFromDescriptorErrors(t *testing.T) {
+	registry := NewRegistry()
+	if err := registry.Register("Duration", TypeOf(time.Duration(0))); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		d       *TypeDescriptor
+		wantErr error
+	}{
+		{name: "nil", d: nil, wantErr: ErrNilType},
+		{name: "unknown kind", d: &TypeDescriptor{Kind: "tuple"}, wantErr: ErrInvalidArgument},
+		{name: "unknown name", d: &TypeDescriptor{Kind: "struct", Name: "Order"}, wantErr: ErrNotFound},
+		{name: "name of another kind", d: &TypeDescriptor{Kind: "string", Name: "Duration"}, wantErr: ErrWrongKind},
+		{name: "missing element", d: &TypeDescriptor{Kind: "slice"}, wantErr: ErrNilType},
+		{name: "bad channel direction", d: &TypeDescriptor{Kind: "chan", Dir: "sideways", Elem: &TypeDescriptor{Kind: "int"}}, wantErr: ErrInvalidArgument},
+		{name: "negative array length", d: &TypeDescriptor{Kind: "array", Len: -1, Elem: &TypeDescriptor{Kind: "int"}}, wantErr: ErrInvalidArgument},
+		{name: "incomparable key", d: &TypeDescriptor{Kind: "map", Key: &TypeDescriptor{Kind: "slice", Elem: &TypeDescriptor{Kind: "int"}}, Elem: &TypeDescriptor{Kind: "int"}}, wantErr: ErrWrongType},
+		{name: "invalid field", d: &TypeDescriptor{Kind: "struct", Fields: []FieldDescriptor{{Name: "a", Type: &TypeDescriptor{Kind: "int"}}}}, wantErr: ErrInvalidArgument},
+		{name: "variadic without slice", d: &TypeDescriptor{Kind: "func", In: []*TypeDescriptor{{Kind: "int"}}, Variadic: true}, wantErr: ErrWrongKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := TypeFromDescriptor(tt.d, registry); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TypeFromDescriptor() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors returned (wrapped in a *ValueError) by the functions in this package.
+// Use errors.Is to test for them.
+var (
+	ErrWrongKind       = errors.New("wrong kind")
+	ErrWrongType       = errors.New("wrong type")
+	ErrUnaddressable   = errors.New("unaddressable value")
+	ErrOutOfRange      = errors.New("index out of range")
+	ErrInvalidValue    = errors.New("zero Value")
+	ErrUnexported      = errors.New("value obtained using unexported field")
+	ErrNilType         = errors.New("nil type")
+	ErrNilValue        = errors.New("nil value")
+	ErrInvalidArgument = errors.New("invalid argument")
+	ErrInvalidTag      = errors.New("malformed struct tag")
+	ErrClosed          = errors.New("channel is closed")
+	ErrNotFound        = errors.New("not found")
+	ErrAmbiguous       = errors.New("ambiguous name")
+	ErrOverflow        = errors.New("value overflows type")
+	ErrTruncated       = errors.New("value would be truncated")
+	ErrSignLoss        = errors.New("negative value for unsigned type")
+	ErrNaN             = errors.New("NaN has no integer value")
+	ErrInf             = errors.New("infinity has no integer value")
+	ErrInvalidPath     = errors.New("malformed path")
+	ErrInvalidTypeExpr = errors.New("malformed type expression")
+	ErrUnsafeDisabled  = errors.New("unsafe access to unexported fields is disabled")
+	ErrNoEntry         = errors.New("map iterator is not positioned on an entry")
+)
+
+// ValueError is the error returned when a safereflect operation can not be performed. Err is one
+// of the sentinel errors above, Expected and Actual describe the kind or type that was expected
+// and the one that was found, and Index is the offending index or -1 if no index is involved.
+type ValueError struct {
+	Method   string
+	Err      error
+	Expected string
+	Actual   string
+	Index    int
+}
+
+func (e *ValueError) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Method)
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	if e.Index >= 0 {
+		b.WriteString(" at index ")
+		b.WriteString(strconv.Itoa(e.Index))
+	}
+	if e.Expected != "" {
+		b.WriteString(": expected ")
+		b.WriteString(e.Expected)
+		if e.Actual != "" {
+			b.WriteString(", got ")
+			b.WriteString(e.Actual)
+		}
+	} else if e.Actual != "" {
+		b.WriteString(": got ")
+		b.WriteString(e.Actual)
+	}
+	return b.String()
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+func newError(method string, err error) *ValueError {
+	return &ValueError{Method: method, Err: err, Index: -1}
+}
+
+func kindError(method string, expected string, actual reflect.Kind) *ValueError {
+	e := newError(method, ErrWrongKind)
+	e.Expected = expected
+	e.Actual = actual.String()
+	return e
+}
+
+func typeError(method string, expected, actual reflect.Type) *ValueError {
+	e := newError(method, ErrWrongType)
+	e.Expected = typeString(expected)
+	e.Actual = typeString(actual)
+	return e
+}
+
+func rangeError(method string, index, length int) *ValueError {
+	e := newError(method, ErrOutOfRange)
+	e.Index = index
+	e.Expected = "[0, " + strconv.Itoa(length) + ")"
+	return e
+}
+
+func argError(method string, expected, actual string) *ValueError {
+	e := newError(method, ErrInvalidArgument)
+	e.Expected = expected
+	e.Actual = actual
+	return e
+}
+
+func typeString(t reflect.Type) string {
+	if t == nil {
+		return "nil"
+	}
+	return t.String()
+}
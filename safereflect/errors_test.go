@@ -0,0 +1,111 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValueErrorError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  *ValueError
+		want string
+	}{
+		{
+			name: "sentinel only",
+			err:  newError("reflect.Value.Set", ErrUnaddressable),
+			want: "reflect.Value.Set: unaddressable value",
+		},
+		{
+			name: "expected and actual",
+			err:  kindError("reflect.Value.Elem", "ptr or interface", reflect.Int),
+			want: "reflect.Value.Elem: wrong kind: expected ptr or interface, got int",
+		},
+		{
+			name: "index",
+			err:  rangeError("reflect.Value.Index", 3, 2),
+			want: "reflect.Value.Index: index out of range at index 3: expected [0, 2)",
+		},
+		{
+			name: "actual only",
+			err:  &ValueError{Method: "safereflect.DeepCopy", Err: ErrUnexported, Actual: "p", Index: -1},
+			want: "safereflect.DeepCopy: value obtained using unexported field: got p",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.want {
+				t.Errorf("Error() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValueErrors(t *testing.T) {
+	n := 1
+	tests := []struct {
+		name     string
+		call     func() error
+		sentinel error
+		method   string
+	}{
+		{
+			name:     "Index out of range",
+			call:     func() error { _, err := ValueOf([]int{1}).Index(1); return err },
+			sentinel: ErrOutOfRange,
+			method:   "reflect.Value.Index",
+		},
+		{
+			name:     "Elem of int",
+			call:     func() error { _, err := ValueOf(1).Elem(); return err },
+			sentinel: ErrWrongKind,
+			method:   "reflect.Value.Elem",
+		},
+		{
+			name:     "SetInt on unaddressable value",
+			call:     func() error { return ValueOf(n).SetInt(2) },
+			sentinel: ErrUnaddressable,
+			method:   "reflect.Value.SetInt",
+		},
+		{
+			name:     "Equal on zero Value",
+			call:     func() error { _, err := Value{}.Equal(ValueOf(1)); return err },
+			sentinel: ErrInvalidValue,
+			method:   "reflect.Value.Equal",
+		},
+		{
+			name:     "Equal with zero Value argument",
+			call:     func() error { _, err := ValueOf(1).Equal(Value{}); return err },
+			sentinel: ErrInvalidValue,
+			method:   "reflect.Value.Equal",
+		},
+		{
+			name:     "Equal on incomparable value",
+			call:     func() error { _, err := ValueOf([]int{}).Equal(ValueOf(1)); return err },
+			sentinel: ErrWrongType,
+			method:   "reflect.Value.Equal",
+		},
+		{
+			name:     "Implements with nil type",
+			call:     func() error { _, err := TypeOf(1).Implements(nil); return err },
+			sentinel: ErrNilType,
+			method:   "reflect.Type.Implements",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if !errors.Is(err, tt.sentinel) {
+				t.Fatalf("error = %v, want %v", err, tt.sentinel)
+			}
+			var ve *ValueError
+			if !errors.As(err, &ve) {
+				t.Fatalf("error %T is not a *ValueError", err)
+			}
+			if ve.Method != tt.method {
+				t.Errorf("Method = %q, want %q", ve.Method, tt.method)
+			}
+		})
+	}
+}
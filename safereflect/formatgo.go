@@ -0,0 +1,339 @@
+package safereflect
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// A FormatOption configures FormatGo.
+type FormatOption func(*formatConfig)
+
+type formatConfig struct {
+	pkg        string
+	name       string
+	nameNested bool
+}
+
+// FormatPackage sets the name of the package in the generated source. The default is main.
+func FormatPackage(name string) FormatOption {
+	return func(c *formatConfig) {
+		c.pkg = name
+	}
+}
+
+// FormatTypeName sets the name of the declared type. The default is the name of the formatted
+// type, or T if it has none, as is the case for types created by StructOf.
+func FormatTypeName(name string) FormatOption {
+	return func(c *formatConfig) {
+		c.name = name
+	}
+}
+
+// FormatNestedStructs declares every anonymous struct nested in the formatted type as a
+// separate named type, instead of writing it inline. Nested types are named after the type and
+// the field they appear in, so the struct in the Items field of Order is declared as OrderItems.
+// Identical structs share a single declaration.
+func FormatNestedStructs() FormatOption {
+	return func(c *formatConfig) {
+		c.nameNested = true
+	}
+}
+
+// FormatGo renders t as the source of a Go file that declares a type with the same structure,
+// which can be used to turn a type built at run time, such as a StructOf type, into static code.
+// The source is formatted by go/format and imports the packages of the named types t refers to.
+// Struct tags and embedded fields are kept. A named t is declared with its underlying type,
+// while the named types it refers to are referenced by name, so they must be exported, not
+// generic, and not declared in package main.
+func FormatGo(t Type, opts ...FormatOption) ([]byte, error) {
+	const method = "safereflect.FormatGo"
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError(method, ErrNilType)
+	}
+	rt := t.ReflectType()
+	f := &goFormatter{
+		cfg:     formatConfig{pkg: "main"},
+		imports: make(map[string]formatImport),
+		nested:  make(map[reflect.Type]string),
+		used:    make(map[string]bool),
+	}
+	if rt.Name() != "" && rt.PkgPath() != "" && !strings.ContainsRune(rt.Name(), '[') {
+		f.cfg.name = rt.Name()
+	} else {
+		f.cfg.name = "T"
+	}
+	for _, opt := range opts {
+		opt(&f.cfg)
+	}
+	if !token.IsIdentifier(f.cfg.pkg) {
+		return nil, argError(method, "package name", strconv.Quote(f.cfg.pkg))
+	}
+	if !token.IsIdentifier(f.cfg.name) {
+		return nil, argError(method, "type name", strconv.Quote(f.cfg.name))
+	}
+
+	f.used[f.cfg.name] = true
+	if err := f.declare(f.cfg.name, rt); err != nil {
+		return nil, err
+	}
+
+	var src strings.Builder
+	src.WriteString("package " + f.cfg.pkg + "\n")
+	if len(f.imports) > 0 {
+		paths := make([]string, 0, len(f.imports))
+		for path := range f.imports {
+			paths = append(paths, path)
+		}
+		slices.Sort(paths)
+		src.WriteString("\nimport (\n")
+		for _, path := range paths {
+			imp := f.imports[path]
+			if imp.alias != imp.name {
+				src.WriteString(imp.alias + " ")
+			}
+			src.WriteString(strconv.Quote(path) + "\n")
+		}
+		src.WriteString(")\n")
+	}
+	for _, decl := range f.decls {
+		src.WriteString(decl)
+	}
+	out, err := format.Source([]byte(src.String()))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", method, err)
+	}
+	return out, nil
+}
+
+// A formatImport is an imported package. The alias differs from the package name when two
+// packages with the same name are imported.
+type formatImport struct {
+	name  string
+	alias string
+}
+
+type goFormatter struct {
+	cfg     formatConfig
+	imports map[string]formatImport
+	nested  map[reflect.Type]string // names of the nested structs declared separately
+	used    map[string]bool         // type names in use
+	decls   []string
+}
+
+// declare adds the declaration of a type with the given name and the structure of t. Nested
+// structs declared on the way come after it.
+func (f *goFormatter) declare(name string, t reflect.Type) error {
+	i := len(f.decls)
+	f.decls = append(f.decls, "")
+	var b strings.Builder
+	b.WriteString("\ntype " + name + " ")
+	if err := f.literal(&b, t, name); err != nil {
+		return err
+	}
+	b.WriteString("\n")
+	f.decls[i] = b.String()
+	return nil
+}
+
+// typeExpr writes the expression referring to t. hint is the name a nested struct would be
+// declared under.
+func (f *goFormatter) typeExpr(b *strings.Builder, t reflect.Type, hint string) error {
+	if t.Name() != "" {
+		return f.named(b, t)
+	}
+	if t.Kind() == reflect.Struct && f.cfg.nameNested {
+		name, ok := f.nested[t]
+		if !ok {
+			name = hint
+			for n := 2; f.used[name]; n++ {
+				name = hint + strconv.Itoa(n)
+			}
+			f.used[name] = true
+			f.nested[t] = name
+			if err := f.declare(name, t); err != nil {
+				return err
+			}
+		}
+		b.WriteString(name)
+		return nil
+	}
+	return f.literal(b, t, hint)
+}
+
+// named writes the qualified name of the named type t.
+func (f *goFormatter) named(b *strings.Builder, t reflect.Type) error {
+	if t.Kind() == reflect.UnsafePointer && t.PkgPath() == "" {
+		b.WriteString(f.importPackage("unsafe", "unsafe") + ".Pointer")
+		return nil
+	}
+	if t.PkgPath() == "" {
+		b.WriteString(t.Name()) // predeclared
+		return nil
+	}
+	if strings.ContainsRune(t.Name(), '[') {
+		return argError("safereflect.FormatGo", "non-generic named type", t.String())
+	}
+	if !token.IsExported(t.Name()) || t.PkgPath() == "main" {
+		return argError("safereflect.FormatGo", "exported type of an importable package", t.String())
+	}
+	b.WriteString(f.importPackage(t.PkgPath(), strings.TrimSuffix(t.String(), "."+t.Name())) + "." + t.Name())
+	return nil
+}
+
+// importPackage imports the package with the given path and name and returns the name it is
+// referred to by.
+func (f *goFormatter) importPackage(path, name string) string {
+	if imp, ok := f.imports[path]; ok {
+		return imp.alias
+	}
+	alias := name
+	for n := 2; f.aliasUsed(alias); n++ {
+		alias = name + strconv.Itoa(n)
+	}
+	f.imports[path] = formatImport{name, alias}
+	return alias
+}
+
+func (f *goFormatter) aliasUsed(alias string) bool {
+	for _, imp := range f.imports {
+		if imp.alias == alias {
+			return true
+		}
+	}
+	return false
+}
+
+// literal writes the type literal of the underlying type of t.
+func (f *goFormatter) literal(b *strings.Builder, t reflect.Type, hint string) error {
+	switch t.Kind() {
+	case reflect.UnsafePointer:
+		b.WriteString(f.importPackage("unsafe", "unsafe") + ".Pointer")
+	case reflect.Array:
+		b.WriteString("[" + strconv.Itoa(t.Len()) + "]")
+		return f.typeExpr(b, t.Elem(), hint)
+	case reflect.Slice:
+		b.WriteString("[]")
+		return f.typeExpr(b, t.Elem(), hint)
+	case reflect.Pointer:
+		b.WriteString("*")
+		return f.typeExpr(b, t.Elem(), hint)
+	case reflect.Map:
+		b.WriteString("map[")
+		if err := f.typeExpr(b, t.Key(), hint); err != nil {
+			return err
+		}
+		b.WriteString("]")
+		return f.typeExpr(b, t.Elem(), hint)
+	case reflect.Chan:
+		elem := t.Elem()
+		switch t.ChanDir() {
+		case reflect.RecvDir:
+			b.WriteString("<-chan ")
+		case reflect.SendDir:
+			b.WriteString("chan<- ")
+		default:
+			// chan <-chan T would be read as chan<- chan T.
+			if elem.Name() == "" && elem.Kind() == reflect.Chan && elem.ChanDir() == reflect.RecvDir {
+				b.WriteString("chan (")
+				if err := f.typeExpr(b, elem, hint); err != nil {
+					return err
+				}
+				b.WriteString(")")
+				return nil
+			}
+			b.WriteString("chan ")
+		}
+		return f.typeExpr(b, elem, hint)
+	case reflect.Func:
+		b.WriteString("func")
+		return f.signature(b, t, hint)
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			b.WriteString("any")
+			return nil
+		}
+		b.WriteString("interface {\n")
+		for i := 0; i < t.NumMethod(); i++ {
+			m := t.Method(i)
+			if !m.IsExported() {
+				return argError("safereflect.FormatGo", "interface with exported methods", t.String())
+			}
+			b.WriteString(m.Name)
+			if err := f.signature(b, m.Type, hint); err != nil {
+				return err
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	case reflect.Struct:
+		b.WriteString("struct {\n")
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.Anonymous {
+				b.WriteString(field.Name + " ")
+			}
+			if err := f.typeExpr(b, field.Type, hint+field.Name); err != nil {
+				return err
+			}
+			if field.Tag != "" {
+				b.WriteString(" " + tagLiteral(string(field.Tag)))
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+	default:
+		b.WriteString(Kind(t.Kind()).String())
+	}
+	return nil
+}
+
+// signature writes the parameters and results of the function type t.
+func (f *goFormatter) signature(b *strings.Builder, t reflect.Type, hint string) error {
+	b.WriteString("(")
+	for i := 0; i < t.NumIn(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		in := t.In(i)
+		if t.IsVariadic() && i == t.NumIn()-1 {
+			b.WriteString("...")
+			in = in.Elem()
+		}
+		if err := f.typeExpr(b, in, hint); err != nil {
+			return err
+		}
+	}
+	b.WriteString(")")
+	if t.NumOut() == 0 {
+		return nil
+	}
+	b.WriteString(" ")
+	if t.NumOut() > 1 {
+		b.WriteString("(")
+	}
+	for i := 0; i < t.NumOut(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		if err := f.typeExpr(b, t.Out(i), hint); err != nil {
+			return err
+		}
+	}
+	if t.NumOut() > 1 {
+		b.WriteString(")")
+	}
+	return nil
+}
+
+// tagLiteral returns tag as a Go string literal, preferring a raw string.
+func tagLiteral(tag string) string {
+	if strconv.CanBackquote(tag) {
+		return "`" + tag + "`"
+	}
+	return strconv.Quote(tag)
+}
@@ -0,0 +1,133 @@
+package safereflect
+
+import (
+	"errors"
+	"go/parser"
+	"go/token"
+	htmltemplate "html/template"
+	"io"
+	"testing"
+	"text/template"
+	"time"
+)
+
+type formatBox[T any] struct {
+	V T
+}
+
+func mustStructOf(t *testing.T, fields ...StructField) Type {
+	t.Helper()
+	typ, err := StructOf(fields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return typ
+}
+
+func TestFormatGo(t *testing.T) {
+	item := mustStructOf(t,
+		StructField{Name: "SKU", Type: TypeOf(""), Tag: `json:"sku"`},
+		StructField{Name: "Price", Type: TypeOf(0.0)},
+	)
+	order := mustStructOf(t,
+		StructField{Name: "ID", Type: TypeOf(0), Tag: `json:"id"`},
+		StructField{Name: "Items", Type: SliceOf(item)},
+		StructField{Name: "Backup", Type: item},
+		StructField{Name: "At", Type: TypeOf(time.Time{})},
+	)
+
+	tests := []struct {
+		name string
+		typ  Type
+		opts []FormatOption
+		want string
+	}{
+		{
+			name: "struct with tags and imports",
+			typ:  order,
+			opts: []FormatOption{FormatPackage("orders"), FormatTypeName("Order")},
+			want: "package orders\n\nimport (\n\t\"time\"\n)\n\ntype Order struct {\n" +
+				"\tID    int `json:\"id\"`\n" +
+				"\tItems []struct {\n\t\tSKU   string `json:\"sku\"`\n\t\tPrice float64\n\t}\n" +
+				"\tBackup struct {\n\t\tSKU   string `json:\"sku\"`\n\t\tPrice float64\n\t}\n" +
+				"\tAt time.Time\n}\n",
+		},
+		{
+			name: "nested structs",
+			typ:  order,
+			opts: []FormatOption{FormatTypeName("Order"), FormatNestedStructs()},
+			want: "package main\n\nimport (\n\t\"time\"\n)\n\ntype Order struct {\n" +
+				"\tID     int `json:\"id\"`\n\tItems  []OrderItems\n\tBackup OrderItems\n\tAt     time.Time\n}\n\n" +
+				"type OrderItems struct {\n\tSKU   string `json:\"sku\"`\n\tPrice float64\n}\n",
+		},
+		{
+			name: "default name",
+			typ:  TypeOf(map[string][]int{}),
+			want: "package main\n\ntype T map[string][]int\n",
+		},
+		{
+			name: "named type",
+			typ:  TypeOf(Marker{}),
+			want: "package main\n\ntype Marker struct {\n}\n",
+		},
+		{
+			name: "import aliases",
+			typ: mustStructOf(t,
+				StructField{Name: "Text", Type: TypeOf(&template.Template{})},
+				StructField{Name: "HTML", Type: TypeOf(&htmltemplate.Template{})},
+			),
+			want: "package main\n\nimport (\n\ttemplate2 \"html/template\"\n\t\"text/template\"\n)\n\n" +
+				"type T struct {\n\tText *template.Template\n\tHTML *template2.Template\n}\n",
+		},
+		{
+			name: "funcs, channels and interfaces",
+			typ: mustStructOf(t,
+				StructField{Name: "F", Type: TypeOf(func(int, ...string) (bool, error) { return false, nil })},
+				StructField{Name: "C", Type: TypeFor[chan (<-chan int)]()},
+				StructField{Name: "R", Type: TypeFor[io.Reader]()},
+				StructField{Name: "A", Type: TypeFor[any]()},
+				StructField{Name: "I", Type: TypeFor[interface{ Close() error }]()},
+			),
+			want: "package main\n\nimport (\n\t\"io\"\n)\n\ntype T struct {\n" +
+				"\tF func(int, ...string) (bool, error)\n\tC chan (<-chan int)\n\tR io.Reader\n\tA any\n" +
+				"\tI interface {\n\t\tClose() error\n\t}\n}\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			src, err := FormatGo(tt.typ, tt.opts...)
+			if err != nil {
+				t.Fatalf("FormatGo() error = %v", err)
+			}
+			if string(src) != tt.want {
+				t.Errorf("FormatGo() =\n%s\nwant\n%s", src, tt.want)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), "", src, 0); err != nil {
+				t.Errorf("FormatGo() output does not parse: %v", err)
+			}
+		})
+	}
+}
+
+func TestFormatGoErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     Type
+		opts    []FormatOption
+		wantErr error
+	}{
+		{name: "nil type", typ: nil, wantErr: ErrNilType},
+		{name: "bad package name", typ: TypeOf(0), opts: []FormatOption{FormatPackage("my-pkg")}, wantErr: ErrInvalidArgument},
+		{name: "bad type name", typ: TypeOf(0), opts: []FormatOption{FormatTypeName("1T")}, wantErr: ErrInvalidArgument},
+		{name: "unexported named type", typ: TypeOf([]calc{}), wantErr: ErrInvalidArgument},
+		{name: "generic named type", typ: TypeOf([]formatBox[int]{}), wantErr: ErrInvalidArgument},
+		{name: "unexported interface method", typ: TypeFor[interface{ m() }](), wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := FormatGo(tt.typ, tt.opts...); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("FormatGo() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
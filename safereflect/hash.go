@@ -0,0 +1,238 @@
+package safereflect
+
+import (
+	"encoding/binary"
+	"hash"
+	"hash/fnv"
+	"math"
+	"reflect"
+)
+
+// A HashOption configures Hash.
+type HashOption func(*hashConfig)
+
+type hashConfig struct {
+	ignoreTags []tagMatch
+}
+
+// HashIgnoreTag leaves struct fields whose tag has the given key with the given value out of
+// the hash, for example HashIgnoreTag("hash", "-"). An empty value ignores every field that has
+// the key at all.
+func HashIgnoreTag(key, value string) HashOption {
+	return func(c *hashConfig) {
+		c.ignoreTags = append(c.ignoreTags, tagMatch{key, value})
+	}
+}
+
+// Hash returns a 64-bit FNV-1a hash of the contents of v. It is deterministic across runs and
+// processes, and acyclic values that DeepEqual reports as equal hash to the same value: pointers
+// are followed rather than hashed by address, maps are hashed independently of their iteration
+// order, and unexported fields are included.
+//
+// A pointer, map or slice that refers back to one of its ancestors is hashed as a marker
+// holding the number of pointers, maps and slices between it and that ancestor, so cyclic
+// values are supported and hash by the shape of their cycles. DeepEqual does not compare that
+// shape, so cyclic values it reports as equal, such as a node pointing to itself and two equal
+// nodes pointing to each other, can hash differently.
+//
+// The dynamic type of v and of every value held by an interface is part of the hash, so int(1)
+// and int64(1) hash differently. Functions, channels and unsafe pointers can not be hashed by
+// content; they are accepted when nil and reported as an error otherwise.
+func Hash(v any, opts ...HashOption) (uint64, error) {
+	h := &hasher{active: make(map[walkVisit]int)}
+	for _, opt := range opts {
+		opt(&h.cfg)
+	}
+	rv := reflect.ValueOf(v)
+	w := fnv.New64a()
+	if !rv.IsValid() {
+		h.writeByte(w, hashNil)
+		return w.Sum64(), nil
+	}
+	h.writeString(w, rv.Type().String())
+	if err := h.hash(w, rv); err != nil {
+		return 0, err
+	}
+	return w.Sum64(), nil
+}
+
+// Markers written ahead of values whose encoding would otherwise be ambiguous.
+const (
+	hashNil byte = iota
+	hashNonNil
+	hashBackRef
+)
+
+type hasher struct {
+	cfg    hashConfig
+	active map[walkVisit]int // depth at which each pointer, map or slice being hashed was entered
+	depth  int
+	path   []hashStep
+	buf    [8]byte
+}
+
+// A hashStep records how the value being hashed was reached. Paths are only formatted when an
+// error is reported.
+type hashStep struct {
+	field string
+	index int
+	key   reflect.Value
+}
+
+func (h *hasher) writeByte(w hash.Hash64, b byte) {
+	h.buf[0] = b
+	w.Write(h.buf[:1])
+}
+
+func (h *hasher) writeUint(w hash.Hash64, n uint64) {
+	binary.LittleEndian.PutUint64(h.buf[:], n)
+	w.Write(h.buf[:])
+}
+
+func (h *hasher) writeFloat(w hash.Hash64, f float64) {
+	switch {
+	case f == 0:
+		f = 0 // -0 == 0
+	case math.IsNaN(f):
+		f = math.NaN()
+	}
+	h.writeUint(w, math.Float64bits(f))
+}
+
+func (h *hasher) writeString(w hash.Hash64, s string) {
+	h.writeUint(w, uint64(len(s)))
+	w.Write([]byte(s))
+}
+
+func (h *hasher) hash(w hash.Hash64, v reflect.Value) error {
+	if ref, ok := reference(v); ok {
+		if depth, ok := h.active[ref]; ok {
+			h.writeByte(w, hashBackRef)
+			h.writeUint(w, uint64(h.depth-depth))
+			return nil
+		}
+		h.active[ref] = h.depth
+		h.depth++
+		defer func() {
+			h.depth--
+			delete(h.active, ref)
+		}()
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			h.writeByte(w, 1)
+		} else {
+			h.writeByte(w, 0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		h.writeUint(w, uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		h.writeUint(w, v.Uint())
+	case reflect.Float32, reflect.Float64:
+		h.writeFloat(w, v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := v.Complex()
+		h.writeFloat(w, real(c))
+		h.writeFloat(w, imag(c))
+	case reflect.String:
+		h.writeString(w, v.String())
+	case reflect.Pointer:
+		if v.IsNil() {
+			h.writeByte(w, hashNil)
+			return nil
+		}
+		h.writeByte(w, hashNonNil)
+		return h.hash(w, v.Elem())
+	case reflect.Interface:
+		if v.IsNil() {
+			h.writeByte(w, hashNil)
+			return nil
+		}
+		h.writeByte(w, hashNonNil)
+		h.writeString(w, v.Elem().Type().String())
+		return h.hash(w, v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice {
+			if v.IsNil() {
+				h.writeByte(w, hashNil)
+				return nil
+			}
+			h.writeByte(w, hashNonNil)
+		}
+		h.writeUint(w, uint64(v.Len()))
+		for i := 0; i < v.Len(); i++ {
+			h.path = append(h.path, hashStep{index: i})
+			if err := h.hash(w, v.Index(i)); err != nil {
+				return err
+			}
+			h.path = h.path[:len(h.path)-1]
+		}
+	case reflect.Map:
+		if v.IsNil() {
+			h.writeByte(w, hashNil)
+			return nil
+		}
+		h.writeByte(w, hashNonNil)
+		h.writeUint(w, uint64(v.Len()))
+		// Every entry is hashed on its own and the results are added up, which does not
+		// depend on the order the entries are visited in.
+		var sum uint64
+		iter := v.MapRange()
+		for iter.Next() {
+			entry := fnv.New64a()
+			h.path = append(h.path, hashStep{index: -1, key: iter.Key()})
+			if err := h.hash(entry, iter.Key()); err != nil {
+				return err
+			}
+			if err := h.hash(entry, iter.Value()); err != nil {
+				return err
+			}
+			h.path = h.path[:len(h.path)-1]
+			sum += entry.Sum64()
+		}
+		h.writeUint(w, sum)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if tagMatches(f.Tag, h.cfg.ignoreTags) {
+				continue
+			}
+			h.path = append(h.path, hashStep{field: f.Name})
+			if err := h.hash(w, v.Field(i)); err != nil {
+				return err
+			}
+			h.path = h.path[:len(h.path)-1]
+		}
+	default:
+		// Functions, channels and unsafe pointers.
+		if v.IsNil() {
+			h.writeByte(w, hashNil)
+			return nil
+		}
+		e := kindError("safereflect.Hash", "hashable value", v.Kind())
+		if path := h.formatPath(); path != "" {
+			e.Actual += " at " + path
+		}
+		return e
+	}
+	return nil
+}
+
+// formatPath formats the path of the value being hashed, for error messages.
+func (h *hasher) formatPath() string {
+	path := ""
+	for _, s := range h.path {
+		switch {
+		case s.field != "":
+			path = fieldPath(path, s.field)
+		case s.index >= 0:
+			path = indexPath(path, s.index)
+		default:
+			path = keyPath(path, s.key)
+		}
+	}
+	return path
+}
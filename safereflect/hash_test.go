@@ -0,0 +1,138 @@
+package safereflect
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+type hashItem struct {
+	Name    string
+	Cached  int `hash:"-"`
+	Version int `hash:"skip"`
+	secret  string
+	Next    *hashItem
+}
+
+func mustHash(t *testing.T, v any, opts ...HashOption) uint64 {
+	t.Helper()
+	h, err := Hash(v, opts...)
+	if err != nil {
+		t.Fatalf("Hash(%v) error = %v", v, err)
+	}
+	return h
+}
+
+func TestHashEqual(t *testing.T) {
+	// Two maps with the same entries, built so that they are likely to iterate in different orders.
+	big1, big2 := make(map[int]string), make(map[int]string)
+	for i := range 100 {
+		big1[i] = strings.Repeat("x", i)
+	}
+	for i := 99; i >= 0; i-- {
+		big2[i] = strings.Repeat("x", i)
+	}
+	// Subslices of one backing array, including one nested inside the slice it was cut from.
+	backing := []int{1, 2, 3}
+	outer := make([]any, 2)
+	outer[0], outer[1] = 1, outer[:1]
+
+	tests := []struct {
+		name string
+		a, b any
+		opts []HashOption
+	}{
+		{name: "map order", a: big1, b: big2},
+		{name: "nested maps", a: map[string]map[int]bool{"a": {1: true, 2: false}}, b: map[string]map[int]bool{"a": {2: false, 1: true}}},
+		{name: "pointers by content", a: &hashItem{Name: "a"}, b: &hashItem{Name: "a"}},
+		{name: "negative zero", a: math.Copysign(0, -1), b: 0.0},
+		{name: "NaN", a: math.NaN(), b: math.NaN()},
+		{name: "nil func", a: (func())(nil), b: (func())(nil)},
+		{name: "ignored tag value", a: hashItem{Cached: 1}, b: hashItem{Cached: 2}, opts: []HashOption{HashIgnoreTag("hash", "-")}},
+		{name: "ignored tag key", a: hashItem{Cached: 1, Version: 1}, b: hashItem{Cached: 2, Version: 2}, opts: []HashOption{HashIgnoreTag("hash", "")}},
+		{name: "untyped nil", a: nil, b: nil},
+		{name: "subslices", a: [][]int{backing[:1], backing[:2], backing}, b: [][]int{{1}, {1, 2}, {1, 2, 3}}},
+		{name: "subslice of an ancestor", a: outer, b: []any{1, []any{1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := mustHash(t, tt.a, tt.opts...), mustHash(t, tt.b, tt.opts...); a != b {
+				t.Errorf("Hash() = %#x and %#x, want equal hashes", a, b)
+			}
+		})
+	}
+}
+
+func TestHashDifferent(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b any
+		opts []HashOption
+	}{
+		{name: "int and int64", a: 1, b: int64(1)},
+		{name: "interface dynamic types", a: []any{1}, b: []any{int64(1)}},
+		{name: "strings", a: "a", b: "b"},
+		{name: "string boundaries", a: []string{"ab", "c"}, b: []string{"a", "bc"}},
+		{name: "nil and empty slice", a: []int(nil), b: []int{}},
+		{name: "nil and empty map", a: map[int]int(nil), b: map[int]int{}},
+		{name: "nil pointer and zero value", a: &hashItem{Next: nil}, b: &hashItem{Next: &hashItem{}}},
+		{name: "map values", a: map[int]int{1: 1, 2: 2}, b: map[int]int{1: 2, 2: 1}},
+		{name: "unexported fields", a: hashItem{secret: "a"}, b: hashItem{secret: "b"}},
+		{name: "tag not ignored by default", a: hashItem{Cached: 1}, b: hashItem{Cached: 2}},
+		{name: "other tag value", a: hashItem{Version: 1}, b: hashItem{Version: 2}, opts: []HashOption{HashIgnoreTag("hash", "-")}},
+		{name: "nil and zero", a: nil, b: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if a, b := mustHash(t, tt.a, tt.opts...), mustHash(t, tt.b, tt.opts...); a == b {
+				t.Errorf("Hash() = %#x for both values, want different hashes", a)
+			}
+		})
+	}
+}
+
+func TestHashCycles(t *testing.T) {
+	loop := func(name string) *hashItem {
+		n := &hashItem{Name: name}
+		n.Next = n
+		return n
+	}
+	a, b := loop("a"), loop("a")
+	if mustHash(t, a) != mustHash(t, b) {
+		t.Error("equal self-referencing values hash differently")
+	}
+	if mustHash(t, a) == mustHash(t, loop("b")) {
+		t.Error("different self-referencing values hash the same")
+	}
+
+	m := map[string]any{}
+	m["self"] = m
+	if _, err := Hash(m); err != nil {
+		t.Errorf("Hash() of a map containing itself error = %v", err)
+	}
+}
+
+func TestHashErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		path string
+	}{
+		{name: "func", v: func() {}},
+		{name: "chan in slice", v: []any{1, make(chan int)}, path: "[1]"},
+		{name: "func in map", v: map[string]any{"f": func() {}}, path: `["f"]`},
+		{name: "func in field", v: struct{ F func() }{F: func() {}}, path: "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Hash(tt.v)
+			if !errors.Is(err, ErrWrongKind) {
+				t.Fatalf("Hash() error = %v, want %v", err, ErrWrongKind)
+			}
+			if tt.path != "" && !strings.HasSuffix(err.Error(), " at "+tt.path) {
+				t.Errorf("Hash() error = %v, want it to name %s", err, tt.path)
+			}
+		})
+	}
+}
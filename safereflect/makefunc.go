@@ -0,0 +1,96 @@
+package safereflect
+
+import (
+	"reflect"
+	"strconv"
+)
+
+var errorType = reflect.TypeFor[error]()
+
+// MakeFunc returns a new function of the given func Type that wraps fn. When called, the new
+// function converts its arguments to a slice of Values and runs fn(args). For variadic
+// signatures the final Value is itself a slice holding the variadic arguments.
+//
+// The results returned by fn are checked against the out types of t: there must be one per
+// result, each assignable to its result type. A zero Value stands for the zero value of that
+// result type. If fn returns an error, or the results do not match the signature, the function
+// returns the zero value for every result and that error as its last result. The last result of
+// t must therefore be of type error; any other signature is rejected with an error wrapping
+// ErrInvalidArgument, since the new function would have no way to report the failure.
+func MakeFunc(t Type, fn func(args []Value) ([]Value, error)) (Value, error) {
+	if t == nil || t.ReflectType() == nil {
+		return Value{}, newError("reflect.MakeFunc", ErrNilType)
+	}
+	if t.Kind() != Func {
+		return Value{}, kindError("reflect.MakeFunc", "func", reflect.Kind(t.Kind()))
+	}
+	if fn == nil {
+		return Value{}, argError("reflect.MakeFunc", "non-nil implementation", "nil")
+	}
+	ft := t.ReflectType()
+	if n := ft.NumOut(); n == 0 || ft.Out(n-1) != errorType {
+		return Value{}, argError("reflect.MakeFunc", "func type with a trailing error result", ft.String())
+	}
+	return makeFunc(ft, fn), nil
+}
+
+// makeFunc is MakeFunc without the signature check. A failure in a function whose signature has
+// no trailing error result is raised as a panic, which Try and Value.Call turn back into an
+// error.
+func makeFunc(ft reflect.Type, fn func(args []Value) ([]Value, error)) Value {
+	errIndex := -1
+	if n := ft.NumOut(); n > 0 && ft.Out(n-1) == errorType {
+		errIndex = n - 1
+	}
+	impl := func(in []reflect.Value) []reflect.Value {
+		args := make([]Value, len(in))
+		for i, a := range in {
+			args[i] = Value{a}
+		}
+		results, err := fn(args)
+		if err == nil {
+			var out []reflect.Value
+			if out, err = funcResults(ft, results); err == nil {
+				return out
+			}
+		}
+		if errIndex < 0 {
+			panic(err)
+		}
+		out := make([]reflect.Value, ft.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(ft.Out(i))
+		}
+		out[errIndex] = reflect.ValueOf(&err).Elem()
+		return out
+	}
+	return Value{reflect.MakeFunc(ft, impl)}
+}
+
+// funcResults converts results to the out types of ft, checking that they match.
+func funcResults(ft reflect.Type, results []Value) ([]reflect.Value, error) {
+	if len(results) != ft.NumOut() {
+		return nil, argError("reflect.MakeFunc", strconv.Itoa(ft.NumOut())+" results", strconv.Itoa(len(results)))
+	}
+	out := make([]reflect.Value, len(results))
+	for i, r := range results {
+		rt := ft.Out(i)
+		out[i] = reflect.New(rt).Elem()
+		if !r.V.IsValid() {
+			continue
+		}
+		if !r.V.CanInterface() {
+			e := newError("reflect.MakeFunc", ErrUnexported)
+			e.Index = i
+			e.Actual = r.V.Type().String()
+			return nil, e
+		}
+		if !r.V.Type().AssignableTo(rt) {
+			e := typeError("reflect.MakeFunc", rt, r.V.Type())
+			e.Index = i
+			return nil, e
+		}
+		out[i].Set(r.V)
+	}
+	return out, nil
+}
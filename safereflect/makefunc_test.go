@@ -0,0 +1,101 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMakeFunc(t *testing.T) {
+	errFailed := errors.New("failed")
+	tests := []struct {
+		name    string
+		fn      func(args []Value) ([]Value, error)
+		wantN   int
+		wantErr error
+	}{
+		{
+			name:  "results match",
+			fn:    func(args []Value) ([]Value, error) { return []Value{args[0], {}}, nil },
+			wantN: 7,
+		},
+		{
+			name:  "zero Value stands for the zero result",
+			fn:    func(args []Value) ([]Value, error) { return []Value{{}, {}}, nil },
+			wantN: 0,
+		},
+		{
+			name:    "implementation error",
+			fn:      func(args []Value) ([]Value, error) { return nil, errFailed },
+			wantErr: errFailed,
+		},
+		{
+			name:    "too few results",
+			fn:      func(args []Value) ([]Value, error) { return []Value{args[0]}, nil },
+			wantErr: ErrInvalidArgument,
+		},
+		{
+			name:    "wrong result type",
+			fn:      func(args []Value) ([]Value, error) { return []Value{ValueOf("7"), {}}, nil },
+			wantErr: ErrWrongType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := MakeFunc(TypeOf(func(int) (int, error) { return 0, nil }), tt.fn)
+			if err != nil {
+				t.Fatalf("MakeFunc() error = %v", err)
+			}
+			f, _ := v.Interface()
+			n, err := f.(func(int) (int, error))(7)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("call error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if n != 0 {
+					t.Errorf("call result = %d, want the zero value on error", n)
+				}
+				return
+			}
+			if n != tt.wantN {
+				t.Errorf("call result = %d, want %d", n, tt.wantN)
+			}
+		})
+	}
+}
+
+func TestMakeFuncVariadic(t *testing.T) {
+	v, err := MakeFunc(TypeOf(func(...int) (int, error) { return 0, nil }), func(args []Value) ([]Value, error) {
+		n, _ := args[0].Len()
+		return []Value{ValueOf(n), {}}, nil
+	})
+	if err != nil {
+		t.Fatalf("MakeFunc() error = %v", err)
+	}
+	f, _ := v.Interface()
+	if got, _ := f.(func(...int) (int, error))(1, 2, 3); got != 3 {
+		t.Errorf("call result = %d, want 3 variadic arguments in one slice", got)
+	}
+}
+
+func TestMakeFuncRejects(t *testing.T) {
+	impl := func(args []Value) ([]Value, error) { return nil, nil }
+	tests := []struct {
+		name    string
+		typ     Type
+		fn      func(args []Value) ([]Value, error)
+		wantErr error
+	}{
+		{name: "nil type", typ: nil, fn: impl, wantErr: ErrNilType},
+		{name: "not a func", typ: TypeOf(0), fn: impl, wantErr: ErrWrongKind},
+		{name: "nil implementation", typ: TypeOf(func() error { return nil }), fn: nil, wantErr: ErrInvalidArgument},
+		{name: "no results", typ: TypeOf(func() {}), fn: impl, wantErr: ErrInvalidArgument},
+		{name: "no trailing error result", typ: TypeOf(func() (error, int) { return nil, 0 }), fn: impl, wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := MakeFunc(tt.typ, tt.fn); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("MakeFunc() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
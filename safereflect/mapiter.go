@@ -0,0 +1,113 @@
+package safereflect
+
+import "reflect"
+
+// A MapIter is an iterator for ranging over a map. See Value.MapRange. Unlike *reflect.MapIter,
+// calling Key or Value before Next, or after Next has returned false, is reported as an error.
+type MapIter struct {
+	m          reflect.Value
+	it         *reflect.MapIter
+	positioned bool
+	done       bool
+}
+
+// MapRange returns a range iterator for a map.
+//
+//	iter, err := m.MapRange()
+//	for iter.Next() {
+//		k, _ := iter.Key()
+//		v, _ := iter.Value()
+//		...
+//	}
+func (v Value) MapRange() (*MapIter, error) {
+	if v.V.Kind() != reflect.Map {
+		return nil, kindError("reflect.Value.MapRange", "map", v.V.Kind())
+	}
+	return &MapIter{m: v.V, it: v.V.MapRange()}, nil
+}
+
+// Next advances the map iterator and reports whether there is another entry. It returns false
+// when the iterator is exhausted or was reset to the zero Value.
+func (iter *MapIter) Next() bool {
+	if iter.it == nil || iter.done {
+		return false
+	}
+	iter.positioned = iter.it.Next()
+	iter.done = !iter.positioned
+	return iter.positioned
+}
+
+// Key returns the key of iter's current map entry.
+func (iter *MapIter) Key() (Value, error) {
+	if !iter.positioned {
+		return Value{}, newError("reflect.MapIter.Key", ErrNoEntry)
+	}
+	return Value{iter.it.Key()}, nil
+}
+
+// Value returns the value of iter's current map entry.
+func (iter *MapIter) Value() (Value, error) {
+	if !iter.positioned {
+		return Value{}, newError("reflect.MapIter.Value", ErrNoEntry)
+	}
+	return Value{iter.it.Value()}, nil
+}
+
+// Reset modifies iter to iterate over v. v must be a map or the zero Value, which releases the
+// map iter was iterating over.
+func (iter *MapIter) Reset(v Value) error {
+	if v.V.IsValid() && v.V.Kind() != reflect.Map {
+		return kindError("reflect.MapIter.Reset", "map", v.V.Kind())
+	}
+	iter.m = v.V
+	iter.positioned, iter.done = false, false
+	if !v.V.IsValid() {
+		iter.it = nil
+		return nil
+	}
+	if iter.it == nil {
+		iter.it = v.V.MapRange()
+		return nil
+	}
+	iter.it.Reset(v.V)
+	return nil
+}
+
+// SetIterKey assigns to v the key of iter's current map entry. It is equivalent to
+// v.Set(iter.Key()), but avoids allocating a new Value.
+func (v Value) SetIterKey(iter *MapIter) error {
+	if err := v.checkSetIter("reflect.Value.SetIterKey", iter); err != nil {
+		return err
+	}
+	if kt := iter.m.Type().Key(); !kt.AssignableTo(v.V.Type()) {
+		return typeError("reflect.Value.SetIterKey", v.V.Type(), kt)
+	}
+	v.V.SetIterKey(iter.it)
+	return nil
+}
+
+// SetIterValue assigns to v the value of iter's current map entry. It is equivalent to
+// v.Set(iter.Value()), but avoids allocating a new Value.
+func (v Value) SetIterValue(iter *MapIter) error {
+	if err := v.checkSetIter("reflect.Value.SetIterValue", iter); err != nil {
+		return err
+	}
+	if et := iter.m.Type().Elem(); !et.AssignableTo(v.V.Type()) {
+		return typeError("reflect.Value.SetIterValue", v.V.Type(), et)
+	}
+	v.V.SetIterValue(iter.it)
+	return nil
+}
+
+func (v Value) checkSetIter(method string, iter *MapIter) error {
+	if iter == nil || !iter.positioned {
+		return newError(method, ErrNoEntry)
+	}
+	if !v.V.CanSet() {
+		return newError(method, ErrUnaddressable)
+	}
+	if !iter.m.CanInterface() {
+		return newError(method, ErrUnexported)
+	}
+	return nil
+}
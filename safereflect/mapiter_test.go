@@ -0,0 +1,111 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMapIter(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2, "c": 3}
+	iter, err := ValueOf(m).MapRange()
+	if err != nil {
+		t.Fatalf("MapRange() error = %v", err)
+	}
+	if _, err := iter.Key(); !errors.Is(err, ErrNoEntry) {
+		t.Errorf("Key() before Next error = %v, want %v", err, ErrNoEntry)
+	}
+
+	got := make(map[string]int)
+	for iter.Next() {
+		k, err := iter.Key()
+		if err != nil {
+			t.Fatalf("Key() error = %v", err)
+		}
+		v, err := iter.Value()
+		if err != nil {
+			t.Fatalf("Value() error = %v", err)
+		}
+		got[k.V.String()] = int(v.V.Int())
+	}
+	if !DeepEqual(got, m) {
+		t.Errorf("iterated %v, want %v", got, m)
+	}
+	if iter.Next() {
+		t.Error("Next() = true after the iterator was exhausted")
+	}
+	if _, err := iter.Value(); !errors.Is(err, ErrNoEntry) {
+		t.Errorf("Value() after the last entry error = %v, want %v", err, ErrNoEntry)
+	}
+
+	if err := iter.Reset(ValueOf(map[string]int{"z": 26})); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if !iter.Next() {
+		t.Fatal("Next() = false after Reset to a non-empty map")
+	}
+	if k, _ := iter.Key(); k.V.String() != "z" {
+		t.Errorf("Key() after Reset = %v, want z", k)
+	}
+
+	if err := iter.Reset(Value{}); err != nil {
+		t.Fatalf("Reset(Value{}) error = %v", err)
+	}
+	if iter.Next() {
+		t.Error("Next() = true after Reset to the zero Value")
+	}
+}
+
+func TestMapIterRejects(t *testing.T) {
+	if _, err := ValueOf([]int{}).MapRange(); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("MapRange() on a slice error = %v, want %v", err, ErrWrongKind)
+	}
+	iter, _ := ValueOf(map[int]int{}).MapRange()
+	if err := iter.Reset(ValueOf(1)); !errors.Is(err, ErrWrongKind) {
+		t.Errorf("Reset() with an int error = %v, want %v", err, ErrWrongKind)
+	}
+}
+
+func TestSetIter(t *testing.T) {
+	m := map[string]int{"a": 1}
+	var (
+		key   string
+		value int
+		wrong float64
+	)
+	tests := []struct {
+		name    string
+		set     func(iter *MapIter) error
+		next    bool
+		wantErr error
+	}{
+		{name: "key", set: func(iter *MapIter) error { return mustValueOf(&key).SetIterKey(iter) }, next: true},
+		{name: "value", set: func(iter *MapIter) error { return mustValueOf(&value).SetIterValue(iter) }, next: true},
+		{name: "before Next", set: func(iter *MapIter) error { return mustValueOf(&key).SetIterKey(iter) }, wantErr: ErrNoEntry},
+		{name: "nil iterator", set: func(*MapIter) error { return mustValueOf(&key).SetIterKey(nil) }, next: true, wantErr: ErrNoEntry},
+		{name: "unaddressable", set: func(iter *MapIter) error { return ValueOf(key).SetIterKey(iter) }, next: true, wantErr: ErrUnaddressable},
+		{name: "wrong value type", set: func(iter *MapIter) error { return mustValueOf(&wrong).SetIterValue(iter) }, next: true, wantErr: ErrWrongType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			iter, _ := ValueOf(m).MapRange()
+			if tt.next {
+				iter.Next()
+			}
+			if err := tt.set(iter); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+	if key != "a" || value != 1 {
+		t.Errorf("key, value = %q, %d, want \"a\", 1", key, value)
+	}
+}
+
+// mustValueOf returns the Value that p points to.
+func mustValueOf(p any) Value {
+	v, err := ValueOf(p).Elem()
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -1,5 +1,7 @@
 package safereflect
 
+import "reflect"
+
 type Method struct {
 	Name    string
 	PkgPath string
@@ -11,3 +13,13 @@ type Method struct {
 func (m Method) IsExported() bool {
 	return m.PkgPath == ""
 }
+
+func toMethod(m reflect.Method) Method {
+	return Method{
+		Name:    m.Name,
+		PkgPath: m.PkgPath,
+		Type:    toType(m.Type),
+		Func:    Value{m.Func},
+		Index:   m.Index,
+	}
+}
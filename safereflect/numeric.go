@@ -0,0 +1,196 @@
+package safereflect
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Number is the set of types whose underlying type is one of Go's int, uint, float or complex
+// types. See ToNumber.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64 |
+		~complex64 | ~complex128
+}
+
+// ConvertNumeric converts the numeric value v to the numeric type t, refusing conversions that
+// would change the value. The error wraps one of the following sentinels:
+//
+//   - ErrOverflow when the value is out of the range of t, such as 300 into an int8;
+//   - ErrSignLoss when a negative value is converted to an unsigned type;
+//   - ErrTruncated when a float has a fractional part that an integer type can not hold, an
+//     integer has more significant bits than a float type can hold, or a complex number with a
+//     non-zero imaginary part is converted to a type without one;
+//   - ErrNaN or ErrInf when NaN or an infinity is converted to an integer type.
+//
+// Converting between float types only checks the range, so float64(0.1) converts to float32
+// with the usual rounding, and NaN and infinities are kept as they are.
+func ConvertNumeric(v Value, t Type) (Value, error) {
+	const method = "safereflect.ConvertNumeric"
+	if t == nil || t.ReflectType() == nil {
+		return Value{}, newError(method, ErrNilType)
+	}
+	if !v.V.IsValid() {
+		return Value{}, newError(method, ErrInvalidValue)
+	}
+	rt := t.ReflectType()
+	if !isNumericKind(v.V.Kind()) {
+		return Value{}, kindError(method, "int, uint, float or complex", v.V.Kind())
+	}
+	if !isNumericKind(rt.Kind()) {
+		return Value{}, kindError(method, "int, uint, float or complex", rt.Kind())
+	}
+	if !v.V.CanInterface() {
+		return Value{}, newError(method, ErrUnexported)
+	}
+	out, err := convertNumeric(v.V, rt)
+	if err != nil {
+		e := newError(method, err)
+		e.Expected = rt.String()
+		e.Actual = formatNumber(v.V)
+		return Value{}, e
+	}
+	return Value{out}, nil
+}
+
+// ToNumber converts the numeric value held by v to T, following the rules of ConvertNumeric.
+// It is convenient for values decoded from JSON, where every number is a float64.
+func ToNumber[T Number](v any) (T, error) {
+	out, err := ConvertNumeric(ValueOf(v), TypeFor[T]())
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return out.V.Interface().(T), nil
+}
+
+// convertNumeric converts x to t, both of numeric kinds, and returns the sentinel describing
+// why the conversion would change the value, if it would.
+func convertNumeric(x reflect.Value, t reflect.Type) (reflect.Value, error) {
+	out := reflect.New(t).Elem()
+	switch x.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := x.Int()
+		switch out.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if out.OverflowInt(n) {
+				return reflect.Value{}, ErrOverflow
+			}
+			out.SetInt(n)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if n < 0 {
+				return reflect.Value{}, ErrSignLoss
+			}
+			if out.OverflowUint(uint64(n)) {
+				return reflect.Value{}, ErrOverflow
+			}
+			out.SetUint(uint64(n))
+		default:
+			return intToFloat(out, float64(n), func(f float64) bool { return f >= math.MinInt64 && f < math.MaxInt64 && int64(f) == n })
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n := x.Uint()
+		switch out.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if n > math.MaxInt64 || out.OverflowInt(int64(n)) {
+				return reflect.Value{}, ErrOverflow
+			}
+			out.SetInt(int64(n))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			if out.OverflowUint(n) {
+				return reflect.Value{}, ErrOverflow
+			}
+			out.SetUint(n)
+		default:
+			return intToFloat(out, float64(n), func(f float64) bool { return f >= 0 && f < math.MaxUint64 && uint64(f) == n })
+		}
+	case reflect.Float32, reflect.Float64:
+		return floatToNumber(out, x.Float())
+	case reflect.Complex64, reflect.Complex128:
+		c := x.Complex()
+		if out.Kind() == reflect.Complex64 || out.Kind() == reflect.Complex128 {
+			if out.OverflowComplex(c) {
+				return reflect.Value{}, ErrOverflow
+			}
+			out.SetComplex(c)
+			return out, nil
+		}
+		if imag(c) != 0 {
+			return reflect.Value{}, ErrTruncated
+		}
+		return floatToNumber(out, real(c))
+	}
+	return out, nil
+}
+
+// intToFloat stores the integer f, already converted to float64, in the float or complex value
+// out. exact reports whether a float holds the original integer, which is not the case once the
+// integer has more significant bits than the mantissa.
+func intToFloat(out reflect.Value, f float64, exact func(float64) bool) (reflect.Value, error) {
+	if out.Kind() == reflect.Float32 || out.Kind() == reflect.Complex64 {
+		f = float64(float32(f))
+	}
+	if !exact(f) {
+		return reflect.Value{}, ErrTruncated
+	}
+	if out.Kind() == reflect.Float32 || out.Kind() == reflect.Float64 {
+		out.SetFloat(f)
+	} else {
+		out.SetComplex(complex(f, 0))
+	}
+	return out, nil
+}
+
+// floatToNumber stores f in the numeric value out.
+func floatToNumber(out reflect.Value, f float64) (reflect.Value, error) {
+	switch out.Kind() {
+	case reflect.Float32, reflect.Float64:
+		if !math.IsInf(f, 0) && out.OverflowFloat(f) {
+			return reflect.Value{}, ErrOverflow
+		}
+		out.SetFloat(f)
+		return out, nil
+	case reflect.Complex64, reflect.Complex128:
+		if !math.IsInf(f, 0) && out.OverflowComplex(complex(f, 0)) {
+			return reflect.Value{}, ErrOverflow
+		}
+		out.SetComplex(complex(f, 0))
+		return out, nil
+	}
+	switch {
+	case math.IsNaN(f):
+		return reflect.Value{}, ErrNaN
+	case math.IsInf(f, 0):
+		return reflect.Value{}, ErrInf
+	case f < 0 && out.Kind() >= reflect.Uint && out.Kind() <= reflect.Uintptr:
+		return reflect.Value{}, ErrSignLoss
+	case f != math.Trunc(f):
+		return reflect.Value{}, ErrTruncated
+	}
+	if out.Kind() >= reflect.Uint && out.Kind() <= reflect.Uintptr {
+		if f >= math.MaxUint64 || out.OverflowUint(uint64(f)) {
+			return reflect.Value{}, ErrOverflow
+		}
+		out.SetUint(uint64(f))
+		return out, nil
+	}
+	if f < math.MinInt64 || f >= math.MaxInt64 || out.OverflowInt(int64(f)) {
+		return reflect.Value{}, ErrOverflow
+	}
+	out.SetInt(int64(f))
+	return out, nil
+}
+
+func formatNumber(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits())
+	}
+	return strconv.FormatComplex(v.Complex(), 'g', -1, v.Type().Bits())
+}
@@ -0,0 +1,96 @@
+package safereflect
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestConvertNumeric(t *testing.T) {
+	tests := []struct {
+		name    string
+		x       any
+		to      Type
+		want    any
+		wantErr error
+	}{
+		{name: "int to int8", x: 127, to: TypeOf(int8(0)), want: int8(127)},
+		{name: "int to int8 overflow", x: 128, to: TypeOf(int8(0)), wantErr: ErrOverflow},
+		{name: "int to int8 negative overflow", x: -129, to: TypeOf(int8(0)), wantErr: ErrOverflow},
+		{name: "int to uint", x: 3, to: TypeOf(uint(0)), want: uint(3)},
+		{name: "negative int to uint", x: -1, to: TypeOf(uint(0)), wantErr: ErrSignLoss},
+		{name: "uint to int64 overflow", x: uint64(math.MaxUint64), to: TypeOf(int64(0)), wantErr: ErrOverflow},
+		{name: "uint to uint8 overflow", x: uint(256), to: TypeOf(uint8(0)), wantErr: ErrOverflow},
+		{name: "int to float64", x: 1 << 53, to: TypeOf(0.0), want: float64(1 << 53)},
+		{name: "int to float64 truncated", x: 1<<53 + 1, to: TypeOf(0.0), wantErr: ErrTruncated},
+		{name: "int to float32 truncated", x: 1<<24 + 1, to: TypeOf(float32(0)), wantErr: ErrTruncated},
+		{name: "max int64 to float64", x: int64(math.MaxInt64), to: TypeOf(0.0), wantErr: ErrTruncated},
+		{name: "max uint64 to float64", x: uint64(math.MaxUint64), to: TypeOf(0.0), wantErr: ErrTruncated},
+		{name: "int to complex", x: 2, to: TypeOf(complex128(0)), want: complex(2, 0)},
+		{name: "float to int", x: 42.0, to: TypeOf(0), want: 42},
+		{name: "float with fraction to int", x: 1.5, to: TypeOf(0), wantErr: ErrTruncated},
+		{name: "negative float to uint", x: -1.0, to: TypeOf(uint8(0)), wantErr: ErrSignLoss},
+		{name: "float to int8 overflow", x: 200.0, to: TypeOf(int8(0)), wantErr: ErrOverflow},
+		{name: "float to int64 overflow", x: 1e19, to: TypeOf(int64(0)), wantErr: ErrOverflow},
+		{name: "float to uint64 overflow", x: 2e19, to: TypeOf(uint64(0)), wantErr: ErrOverflow},
+		{name: "NaN to int", x: math.NaN(), to: TypeOf(0), wantErr: ErrNaN},
+		{name: "infinity to int", x: math.Inf(-1), to: TypeOf(0), wantErr: ErrInf},
+		{name: "float64 to float32", x: 0.5, to: TypeOf(float32(0)), want: float32(0.5)},
+		{name: "float64 to float32 overflow", x: 1e300, to: TypeOf(float32(0)), wantErr: ErrOverflow},
+		{name: "infinity to float32", x: math.Inf(1), to: TypeOf(float32(0)), want: float32(math.Inf(1))},
+		{name: "real complex to float", x: complex(3, 0), to: TypeOf(0.0), want: 3.0},
+		{name: "real complex to int", x: complex(3, 0), to: TypeOf(0), want: 3},
+		{name: "complex to float", x: complex(3, 1), to: TypeOf(0.0), wantErr: ErrTruncated},
+		{name: "complex128 to complex64 overflow", x: complex(1e300, 0), to: TypeOf(complex64(0)), wantErr: ErrOverflow},
+		{name: "to named type", x: 5, to: TypeOf(time.Duration(0)), want: time.Duration(5)},
+		{name: "from named type", x: time.Duration(5), to: TypeOf(int32(0)), want: int32(5)},
+		{name: "string source", x: "1", to: TypeOf(0), wantErr: ErrWrongKind},
+		{name: "string target", x: 1, to: TypeOf(""), wantErr: ErrWrongKind},
+		{name: "nil type", x: 1, to: nil, wantErr: ErrNilType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ConvertNumeric(ValueOf(tt.x), tt.to)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ConvertNumeric() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ConvertNumeric() error = %v", err)
+			}
+			if v, _ := got.Interface(); v != tt.want {
+				t.Errorf("ConvertNumeric() = %v (%T), want %v (%T)", v, v, tt.want, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvertNumericError(t *testing.T) {
+	_, err := ConvertNumeric(ValueOf(300), TypeOf(int8(0)))
+	want := "safereflect.ConvertNumeric: value overflows type: expected int8, got 300"
+	if err == nil || err.Error() != want {
+		t.Errorf("error = %v, want %s", err, want)
+	}
+	if _, err := ConvertNumeric(Value{}, TypeOf(0)); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ConvertNumeric(Value{}) error = %v, want %v", err, ErrInvalidValue)
+	}
+}
+
+func TestToNumber(t *testing.T) {
+	// Numbers decoded from JSON into an any are float64.
+	if n, err := ToNumber[int](float64(12)); err != nil || n != 12 {
+		t.Errorf("ToNumber[int](12.0) = %v, %v, want 12", n, err)
+	}
+	if n, err := ToNumber[uint8](float64(256)); !errors.Is(err, ErrOverflow) || n != 0 {
+		t.Errorf("ToNumber[uint8](256.0) = %v, %v, want 0 and %v", n, err, ErrOverflow)
+	}
+	if d, err := ToNumber[time.Duration](int64(3)); err != nil || d != 3 {
+		t.Errorf("ToNumber[time.Duration](3) = %v, %v, want 3ns", d, err)
+	}
+	if _, err := ToNumber[int](nil); !errors.Is(err, ErrInvalidValue) {
+		t.Errorf("ToNumber[int](nil) error = %v, want %v", err, ErrInvalidValue)
+	}
+}
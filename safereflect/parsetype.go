@@ -0,0 +1,272 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/scanner"
+	"go/token"
+	"reflect"
+	"strconv"
+)
+
+// A TypeExprError records a failure to parse a type expression passed to ParseType. Offset is
+// the byte offset in Expr of the part of the expression that failed. Err describes the failure;
+// it wraps ErrInvalidTypeExpr for syntax errors and ErrNotFound for unknown type names, and is
+// the *ValueError or *StructFieldError of the type constructor otherwise.
+type TypeExprError struct {
+	Method string
+	Expr   string
+	Offset int
+	Err    error
+}
+
+func (e *TypeExprError) Error() string {
+	return e.Method + ": offset " + strconv.Itoa(e.Offset) + " of type expression " + strconv.Quote(e.Expr) + ": " +
+		e.Err.Error()
+}
+
+func (e *TypeExprError) Unwrap() error {
+	return e.Err
+}
+
+// ParseType parses a type expression written in Go syntax, such as map[string][]*Order or
+// struct{ A int `json:"a"` }, and builds the type it denotes. Arrays, channels with their
+// directions, functions, maps, pointers, slices, struct literals with tags and embedded fields,
+// and the empty interface are supported. Identifiers name the predeclared types, including byte,
+// rune, any and error, or are looked up in registry, as are qualified identifiers such as
+// geo.Point; the registry may be nil when the expression names no other types. Struct fields
+// must be exported, as StructOf can not create unexported ones.
+func ParseType(expr string, registry *Registry) (Type, error) {
+	const method = "safereflect.ParseType"
+	fset := token.NewFileSet()
+	node, err := parser.ParseExprFrom(fset, "", expr, 0)
+	if err != nil {
+		e := &TypeExprError{Method: method, Expr: expr, Err: fmt.Errorf("%w: %v", ErrInvalidTypeExpr, err)}
+		var list scanner.ErrorList
+		if errors.As(err, &list) && len(list) > 0 {
+			e.Offset = list[0].Pos.Offset
+			e.Err = fmt.Errorf("%w: %s", ErrInvalidTypeExpr, list[0].Msg)
+		}
+		return nil, e
+	}
+	p := &typeParser{method: method, expr: expr, fset: fset, registry: registry}
+	t, err := p.parse(node)
+	if err != nil {
+		return nil, err
+	}
+	return toType(t), nil
+}
+
+var (
+	emptyInterfaceType = reflect.TypeFor[any]()
+	typeExprAliases    = map[string]reflect.Type{
+		"any":  emptyInterfaceType,
+		"byte": reflect.TypeFor[byte](),
+		"rune": reflect.TypeFor[rune](),
+	}
+)
+
+type typeParser struct {
+	method   string
+	expr     string
+	fset     *token.FileSet
+	registry *Registry
+}
+
+// fail returns a *TypeExprError for the given node.
+func (p *typeParser) fail(node ast.Node, err error) error {
+	return &TypeExprError{Method: p.method, Expr: p.expr, Offset: p.offset(node.Pos()), Err: err}
+}
+
+// unsupported reports a node that is valid Go but does not denote a type ParseType can build.
+func (p *typeParser) unsupported(node ast.Node, expected string) error {
+	got := p.expr[p.offset(node.Pos()):p.offset(node.End())]
+	return p.fail(node, fmt.Errorf("%w: expected %s, got %s", ErrInvalidTypeExpr, expected, got))
+}
+
+func (p *typeParser) offset(pos token.Pos) int {
+	return p.fset.Position(pos).Offset
+}
+
+func (p *typeParser) parse(node ast.Expr) (reflect.Type, error) {
+	switch n := node.(type) {
+	case *ast.ParenExpr:
+		return p.parse(n.X)
+	case *ast.Ident:
+		return p.lookup(n, n.Name)
+	case *ast.SelectorExpr:
+		pkg, ok := n.X.(*ast.Ident)
+		if !ok {
+			return nil, p.unsupported(n, "type name")
+		}
+		return p.lookup(n, pkg.Name+"."+n.Sel.Name)
+	case *ast.StarExpr:
+		elem, err := p.parse(n.X)
+		if err != nil {
+			return nil, err
+		}
+		return p.build(n, func() (Type, error) { return PointerTo(toType(elem)) })
+	case *ast.ArrayType:
+		elem, err := p.parse(n.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if n.Len == nil {
+			return SliceOf(toType(elem)).ReflectType(), nil
+		}
+		lit, ok := n.Len.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, p.unsupported(n.Len, "array length")
+		}
+		length, err := strconv.ParseInt(lit.Value, 0, 0)
+		if err != nil {
+			return nil, p.fail(lit, fmt.Errorf("%w: array length %s out of range", ErrInvalidTypeExpr, lit.Value))
+		}
+		return p.build(n, func() (Type, error) { return ArrayOf(int(length), toType(elem)) })
+	case *ast.MapType:
+		key, err := p.parse(n.Key)
+		if err != nil {
+			return nil, err
+		}
+		elem, err := p.parse(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		return p.build(n, func() (Type, error) { return MapOf(toType(key), toType(elem)) })
+	case *ast.ChanType:
+		elem, err := p.parse(n.Value)
+		if err != nil {
+			return nil, err
+		}
+		dir := BothDir
+		switch n.Dir {
+		case ast.SEND:
+			dir = SendDir
+		case ast.RECV:
+			dir = RecvDir
+		}
+		return p.build(n, func() (Type, error) { return ChanOf(dir, toType(elem)) })
+	case *ast.FuncType:
+		return p.parseFunc(n)
+	case *ast.StructType:
+		return p.parseStruct(n)
+	case *ast.InterfaceType:
+		if len(n.Methods.List) > 0 {
+			return nil, p.unsupported(n, "empty interface")
+		}
+		return emptyInterfaceType, nil
+	}
+	return nil, p.unsupported(node, "type")
+}
+
+// lookup resolves a type name, first among the predeclared types and then in the registry.
+func (p *typeParser) lookup(node ast.Node, name string) (reflect.Type, error) {
+	if t, ok := predeclared[name]; ok {
+		return t, nil
+	}
+	if t, ok := typeExprAliases[name]; ok {
+		return t, nil
+	}
+	if t, ok := p.registry.Lookup(name); ok {
+		return t.ReflectType(), nil
+	}
+	return nil, p.fail(node, fmt.Errorf("%w: expected predeclared or registered type, got %s", ErrNotFound, name))
+}
+
+// build calls one of the type constructors and attributes its error to node.
+func (p *typeParser) build(node ast.Node, construct func() (Type, error)) (reflect.Type, error) {
+	t, err := construct()
+	if err != nil {
+		return nil, p.fail(node, err)
+	}
+	return t.ReflectType(), nil
+}
+
+func (p *typeParser) parseFunc(n *ast.FuncType) (reflect.Type, error) {
+	if n.TypeParams != nil {
+		return nil, p.unsupported(n.TypeParams, "function type without type parameters")
+	}
+	var in, out []Type
+	variadic := false
+	for i, field := range n.Params.List {
+		typ := field.Type
+		if ell, ok := typ.(*ast.Ellipsis); ok {
+			if i != len(n.Params.List)-1 || len(field.Names) > 1 {
+				return nil, p.unsupported(ell, "... on the final parameter only")
+			}
+			typ, variadic = ell.Elt, true
+		}
+		t, err := p.parse(typ)
+		if err != nil {
+			return nil, err
+		}
+		if variadic {
+			t = SliceOf(toType(t)).ReflectType()
+		}
+		for range max(len(field.Names), 1) {
+			in = append(in, toType(t))
+		}
+	}
+	if n.Results != nil {
+		for _, field := range n.Results.List {
+			t, err := p.parse(field.Type)
+			if err != nil {
+				return nil, err
+			}
+			for range max(len(field.Names), 1) {
+				out = append(out, toType(t))
+			}
+		}
+	}
+	return p.build(n, func() (Type, error) { return FuncOf(in, out, variadic) })
+}
+
+func (p *typeParser) parseStruct(n *ast.StructType) (reflect.Type, error) {
+	var fields []StructField
+	for _, field := range n.Fields.List {
+		t, err := p.parse(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		var tag StructTag
+		if field.Tag != nil {
+			s, err := strconv.Unquote(field.Tag.Value)
+			if err != nil {
+				return nil, p.fail(field.Tag, fmt.Errorf("%w: malformed tag literal", ErrInvalidTypeExpr))
+			}
+			tag = StructTag(s)
+		}
+		if len(field.Names) == 0 {
+			name, ok := embeddedName(field.Type)
+			if !ok {
+				return nil, p.unsupported(field.Type, "embedded type name")
+			}
+			fields = append(fields, StructField{Name: name, Type: toType(t), Tag: tag, Anonymous: true})
+			continue
+		}
+		for _, name := range field.Names {
+			fields = append(fields, StructField{Name: name.Name, Type: toType(t), Tag: tag})
+		}
+	}
+	return p.build(n, func() (Type, error) { return StructOf(fields) })
+}
+
+// embeddedName returns the field name of an embedded field of type expr, which must be a type
+// name or a pointer to one.
+func embeddedName(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		return e.Name, true
+	case *ast.SelectorExpr:
+		return e.Sel.Name, true
+	case *ast.StarExpr:
+		if _, ok := e.X.(*ast.StarExpr); !ok {
+			return embeddedName(e.X)
+		}
+	case *ast.ParenExpr:
+		return embeddedName(e.X)
+	}
+	return "", false
+}
@@ -0,0 +1,105 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseType(t *testing.T) {
+	registry := NewRegistry()
+	for name, typ := range map[string]Type{
+		"Marker":        TypeOf(Marker{}),
+		"time.Duration": TypeOf(time.Duration(0)),
+	} {
+		if err := registry.Register(name, typ); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		expr string
+		want reflect.Type
+	}{
+		{expr: "int", want: reflect.TypeFor[int]()},
+		{expr: "byte", want: reflect.TypeFor[uint8]()},
+		{expr: "rune", want: reflect.TypeFor[int32]()},
+		{expr: "any", want: reflect.TypeFor[any]()},
+		{expr: "interface{}", want: reflect.TypeFor[any]()},
+		{expr: "error", want: reflect.TypeFor[error]()},
+		{expr: "(string)", want: reflect.TypeFor[string]()},
+		{expr: "[]*int", want: reflect.TypeFor[[]*int]()},
+		{expr: "[0x10]byte", want: reflect.TypeFor[[16]byte]()},
+		{expr: "map[string][]float64", want: reflect.TypeFor[map[string][]float64]()},
+		{expr: "chan int", want: reflect.TypeFor[chan int]()},
+		{expr: "<-chan int", want: reflect.TypeFor[<-chan int]()},
+		{expr: "chan<- int", want: reflect.TypeFor[chan<- int]()},
+		{expr: "chan (<-chan int)", want: reflect.TypeFor[chan (<-chan int)]()},
+		{expr: "func(a, b int, rest ...string) (n int, err error)", want: reflect.TypeFor[func(int, int, ...string) (int, error)]()},
+		{expr: "func()", want: reflect.TypeFor[func()]()},
+		{expr: "time.Duration", want: reflect.TypeFor[time.Duration]()},
+		{expr: "map[time.Duration]Marker", want: reflect.TypeFor[map[time.Duration]Marker]()},
+		{
+			expr: "struct { A, B int `x:\"a\"`; C []string }",
+			want: reflect.TypeFor[struct {
+				A, B int `x:"a"`
+				C    []string
+			}](),
+		},
+		{expr: "struct { Marker; N int }", want: reflect.TypeFor[struct {
+			Marker
+			N int
+		}]()},
+		{expr: "struct { *Marker }", want: reflect.TypeFor[struct{ *Marker }]()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := ParseType(tt.expr, registry)
+			if err != nil {
+				t.Fatalf("ParseType() error = %v", err)
+			}
+			if got.ReflectType() != tt.want {
+				t.Errorf("ParseType() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTypeErrors(t *testing.T) {
+	tests := []struct {
+		expr    string
+		offset  int
+		wantErr error
+	}{
+		{expr: "", wantErr: ErrInvalidTypeExpr},
+		{expr: "map[string", offset: 10, wantErr: ErrInvalidTypeExpr},
+		{expr: "[]Order", offset: 2, wantErr: ErrNotFound},
+		{expr: "map[string]geo.Point", offset: 11, wantErr: ErrNotFound},
+		{expr: "a.b.C", offset: 0, wantErr: ErrInvalidTypeExpr},
+		{expr: "1 + 2", offset: 0, wantErr: ErrInvalidTypeExpr},
+		{expr: "[n]int", offset: 1, wantErr: ErrInvalidTypeExpr},
+		{expr: "[99999999999999999999]int", offset: 1, wantErr: ErrInvalidTypeExpr},
+		{expr: "[-1]int", offset: 1, wantErr: ErrInvalidTypeExpr},
+		{expr: "map[[]int]string", offset: 0, wantErr: ErrWrongType},
+		{expr: "interface{ M() }", offset: 0, wantErr: ErrInvalidTypeExpr},
+		{expr: "func[T any]()", offset: 4, wantErr: ErrInvalidTypeExpr},
+		{expr: "struct { a int }", offset: 0, wantErr: ErrInvalidArgument},
+		{expr: "struct { []int }", offset: 9, wantErr: ErrInvalidTypeExpr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			_, err := ParseType(tt.expr, nil)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseType() error = %v, want %v", err, tt.wantErr)
+			}
+			var te *TypeExprError
+			if !errors.As(err, &te) {
+				t.Fatalf("ParseType() error = %v, want a *TypeExprError", err)
+			}
+			if te.Offset != tt.offset {
+				t.Errorf("TypeExprError.Offset = %d, want %d", te.Offset, tt.offset)
+			}
+		})
+	}
+}
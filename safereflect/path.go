@@ -0,0 +1,331 @@
+package safereflect
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// A PathError records a failure to resolve a path passed to Value.Lookup or Value.SetPath.
+// Segment is the segment that failed as written in Path, for example ["sku"] or Price, and
+// Offset is its byte offset in Path. Err describes the failure and usually is a *ValueError.
+type PathError struct {
+	Method  string
+	Path    string
+	Segment string
+	Offset  int
+	Err     error
+}
+
+func (e *PathError) Error() string {
+	if e.Segment == "" {
+		return e.Method + ": path " + strconv.Quote(e.Path) + ": " + e.Err.Error()
+	}
+	return e.Method + ": segment " + e.Segment + " at offset " + strconv.Itoa(e.Offset) + " of path " +
+		strconv.Quote(e.Path) + ": " + e.Err.Error()
+}
+
+func (e *PathError) Unwrap() error {
+	return e.Err
+}
+
+// A pathSegment is one step of a parsed path: a field name, or the contents of a pair of
+// brackets, which index a slice, array or string or select a map entry.
+type pathSegment struct {
+	text   string
+	offset int
+	field  string
+	index  string
+	quoted bool
+}
+
+// parsePath splits a path such as Orders[2].Items["sku"].Price, the form produced by Diff,
+// into its segments. An empty path has no segments and refers to the root value.
+func parsePath(method, path string) ([]pathSegment, error) {
+	var segs []pathSegment
+	pos := 0
+	syntaxError := func(start, at int, expected string) error {
+		got := "end of path"
+		if at < len(path) {
+			r, _ := utf8.DecodeRuneInString(path[at:])
+			got = strconv.QuoteRune(r)
+		}
+		return &PathError{
+			Method:  method,
+			Path:    path,
+			Segment: path[start:min(at+1, len(path))],
+			Offset:  start,
+			Err:     fmt.Errorf("%w: expected %s, got %s", ErrInvalidPath, expected, got),
+		}
+	}
+	for pos < len(path) {
+		start := pos
+		switch {
+		case path[pos] == '[':
+			seg := pathSegment{offset: start}
+			pos++
+			if pos < len(path) && path[pos] == '"' {
+				q, err := strconv.QuotedPrefix(path[pos:])
+				if err != nil {
+					return nil, syntaxError(start, pos, "quoted map key")
+				}
+				seg.index, _ = strconv.Unquote(q)
+				seg.quoted = true
+				pos += len(q)
+			} else {
+				end := strings.IndexByte(path[pos:], ']')
+				if end <= 0 {
+					return nil, syntaxError(start, pos, "index or map key")
+				}
+				seg.index = path[pos : pos+end]
+				pos += end
+			}
+			if pos >= len(path) || path[pos] != ']' {
+				return nil, syntaxError(start, pos, "']'")
+			}
+			pos++
+			seg.text = path[start:pos]
+			segs = append(segs, seg)
+		case path[pos] == '.' && len(segs) > 0:
+			pos++
+			start = pos
+			fallthrough
+		default:
+			for pos < len(path) {
+				r, size := utf8.DecodeRuneInString(path[pos:])
+				if r != '_' && !unicode.IsLetter(r) && (pos == start || !unicode.IsDigit(r)) {
+					break
+				}
+				pos += size
+			}
+			if pos == start {
+				return nil, syntaxError(start, pos, "field name")
+			}
+			segs = append(segs, pathSegment{text: path[start:pos], offset: start, field: path[start:pos]})
+		}
+		if pos < len(path) && path[pos] != '.' && path[pos] != '[' {
+			return nil, syntaxError(start, pos, "'.' or '['")
+		}
+	}
+	return segs, nil
+}
+
+// Lookup returns the value found by following path from v. A path is a sequence of field names
+// separated by dots, indexes such as [3] into slices, arrays and strings, and map keys such as
+// ["sku"] for string keys or [42] for other keys, for example Orders[2].Items["sku"].Price.
+// Pointers and interfaces are followed automatically and promoted fields can be named directly.
+// An empty path returns v. When a segment can not be resolved the error is a *PathError naming
+// that segment.
+func (v Value) Lookup(path string) (Value, error) {
+	segs, err := parsePath("reflect.Value.Lookup", path)
+	if err != nil {
+		return Value{}, err
+	}
+	cur := v.V
+	for _, seg := range segs {
+		if cur, err = stepPath(cur, seg); err != nil {
+			return Value{}, &PathError{Method: "reflect.Value.Lookup", Path: path, Segment: seg.text, Offset: seg.offset, Err: err}
+		}
+	}
+	return Value{cur}, nil
+}
+
+// SetPath assigns x to the value found by following path from v, using the path syntax of
+// Lookup. x must be assignable to the type found there. v itself must be settable unless the
+// path passes through a pointer or a map first. Map entries are not addressable, so setting a
+// path below a map entry copies the entry, sets the path in the copy and stores the copy back
+// into the map; the same is done for values held in a settable interface.
+func (v Value) SetPath(path string, x Value) error {
+	segs, err := parsePath("reflect.Value.SetPath", path)
+	if err != nil {
+		return err
+	}
+	if err := setPath(v.V, segs, x); err != nil {
+		if pe, ok := err.(*PathError); ok {
+			pe.Method, pe.Path = "reflect.Value.SetPath", path
+			return pe
+		}
+		return &PathError{Method: "reflect.Value.SetPath", Path: path, Err: err}
+	}
+	return nil
+}
+
+func setPath(v reflect.Value, segs []pathSegment, x Value) error {
+	if len(segs) == 0 {
+		return Value{v}.SetAssignable(x)
+	}
+	seg := segs[0]
+	wrap := func(err error) error {
+		if _, ok := err.(*PathError); ok {
+			return err
+		}
+		return &PathError{Segment: seg.text, Offset: seg.offset, Err: err}
+	}
+	v, err := derefPath(v)
+	if err != nil {
+		return wrap(err)
+	}
+	if v.Kind() == reflect.Interface {
+		// The value held by an interface is not addressable: work on a copy and put it back.
+		if !v.CanSet() {
+			return wrap(newError("reflect.Value.Set", ErrUnaddressable))
+		}
+		elem := reflect.New(v.Elem().Type()).Elem()
+		elem.Set(v.Elem())
+		if err := setPath(elem, segs, x); err != nil {
+			return wrap(err)
+		}
+		v.Set(elem)
+		return nil
+	}
+	if v.Kind() != reflect.Map {
+		next, err := stepPath(v, seg)
+		if err != nil {
+			return wrap(err)
+		}
+		if err := setPath(next, segs[1:], x); err != nil {
+			return wrap(err)
+		}
+		return nil
+	}
+	if v.IsNil() {
+		return wrap(newError("reflect.Value.SetMapIndex", ErrNilValue))
+	}
+	if !v.CanInterface() {
+		return wrap(newError("reflect.Value.SetMapIndex", ErrUnexported))
+	}
+	key, err := mapKey(v.Type().Key(), seg)
+	if err != nil {
+		return wrap(err)
+	}
+	elem := reflect.New(v.Type().Elem()).Elem()
+	if len(segs) > 1 {
+		cur := v.MapIndex(key)
+		if !cur.IsValid() {
+			return wrap(missingKey(key))
+		}
+		elem.Set(cur)
+	}
+	if err := setPath(elem, segs[1:], x); err != nil {
+		return wrap(err)
+	}
+	v.SetMapIndex(key, elem)
+	return nil
+}
+
+// derefPath follows pointers, and interfaces holding pointers, until it reaches a value that a
+// path segment can be applied to.
+func derefPath(v reflect.Value) (reflect.Value, error) {
+	for {
+		switch v.Kind() {
+		case reflect.Pointer:
+			if v.IsNil() {
+				e := newError("reflect.Value.Elem", ErrNilValue)
+				e.Actual = v.Type().String()
+				return reflect.Value{}, e
+			}
+			v = v.Elem()
+		case reflect.Interface:
+			if v.IsNil() {
+				e := newError("reflect.Value.Elem", ErrNilValue)
+				e.Actual = v.Type().String()
+				return reflect.Value{}, e
+			}
+			if v.Elem().Kind() != reflect.Pointer {
+				return v, nil
+			}
+			v = v.Elem()
+		default:
+			return v, nil
+		}
+	}
+}
+
+// stepPath applies a single segment to v.
+func stepPath(v reflect.Value, seg pathSegment) (reflect.Value, error) {
+	if !v.IsValid() {
+		return reflect.Value{}, newError("reflect.Value.Lookup", ErrInvalidValue)
+	}
+	v, err := derefPath(v)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if seg.field != "" {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, kindError("reflect.Value.FieldByName", "struct", v.Kind())
+		}
+		sf, err := toType(v.Type()).LookupField(seg.field)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		f, err := Value{v}.FieldByIndexErr(sf.Index)
+		return f.V, err
+	}
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.String:
+		i, err := strconv.Atoi(seg.index)
+		if err != nil || seg.quoted {
+			return reflect.Value{}, argError("reflect.Value.Index", "integer index", seg.text)
+		}
+		if i < 0 || i >= v.Len() {
+			return reflect.Value{}, rangeError("reflect.Value.Index", i, v.Len())
+		}
+		return v.Index(i), nil
+	case reflect.Map:
+		key, err := mapKey(v.Type().Key(), seg)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := v.MapIndex(key)
+		if !out.IsValid() {
+			return reflect.Value{}, missingKey(key)
+		}
+		return out, nil
+	}
+	return reflect.Value{}, kindError("reflect.Value.Index", "slice, array, string or map", v.Kind())
+}
+
+// mapKey converts the contents of a bracket segment to a key of type t. Quoted keys are used for
+// string keys, while booleans and numbers are parsed according to the kind of t.
+func mapKey(t reflect.Type, seg pathSegment) (reflect.Value, error) {
+	key := reflect.New(t).Elem()
+	var err error
+	switch t.Kind() {
+	case reflect.String:
+		key.SetString(seg.index)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(seg.index)
+		key.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(seg.index, 10, t.Bits())
+		key.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		n, err = strconv.ParseUint(seg.index, 10, t.Bits())
+		key.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(seg.index, t.Bits())
+		key.SetFloat(f)
+	default:
+		return reflect.Value{}, argError("reflect.Value.MapIndex", "map with a string, bool or numeric key", t.String())
+	}
+	if err != nil || (seg.quoted && t.Kind() != reflect.String) {
+		return reflect.Value{}, argError("reflect.Value.MapIndex", t.String()+" key", seg.text)
+	}
+	return key, nil
+}
+
+func missingKey(key reflect.Value) *ValueError {
+	e := newError("reflect.Value.MapIndex", ErrNotFound)
+	e.Actual = keyPath("", key)
+	return e
+}
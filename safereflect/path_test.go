@@ -0,0 +1,161 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+)
+
+type pathItem struct {
+	SKU   string
+	Price float64
+}
+
+type pathOrder struct {
+	Items  map[string]pathItem
+	Lines  []pathItem
+	Meta   any
+	Ptr    *pathItem
+	Counts map[int]int
+	Flags  map[bool]string
+	Name   string
+}
+
+func newPathOrder() *pathOrder {
+	return &pathOrder{
+		Items:  map[string]pathItem{"sku": {SKU: "sku", Price: 1}},
+		Lines:  []pathItem{{SKU: "a"}, {SKU: "b", Price: 2}},
+		Meta:   map[string]any{"tags": []string{"x", "y"}, "item": pathItem{SKU: "m"}},
+		Ptr:    &pathItem{SKU: "p"},
+		Counts: map[int]int{42: 7},
+		Flags:  map[bool]string{true: "on"},
+		Name:   "order",
+	}
+}
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    any
+		wantErr error
+		segment string
+	}{
+		{path: "Name", want: "order"},
+		{path: `Items["sku"].Price`, want: 1.0},
+		{path: "Lines[1].Price", want: 2.0},
+		{path: "Ptr.SKU", want: "p"},
+		{path: `Meta["tags"][1]`, want: "y"},
+		{path: `Meta["item"].SKU`, want: "m"},
+		{path: "Counts[42]", want: 7},
+		{path: "Flags[true]", want: "on"},
+		{path: "Name[0]", want: byte('o')},
+		{path: "Missing", wantErr: ErrNotFound, segment: "Missing"},
+		{path: `Items["nope"]`, wantErr: ErrNotFound, segment: `["nope"]`},
+		{path: "Lines[5]", wantErr: ErrOutOfRange, segment: "[5]"},
+		{path: `Lines["0"]`, wantErr: ErrInvalidArgument, segment: `["0"]`},
+		{path: `Counts["42"]`, wantErr: ErrInvalidArgument, segment: `["42"]`},
+		{path: "Name.Len", wantErr: ErrWrongKind, segment: "Len"},
+		{path: "Lines[", wantErr: ErrInvalidPath},
+		{path: `Items["sku`, wantErr: ErrInvalidPath},
+		{path: "Lines[0]x", wantErr: ErrInvalidPath},
+		{path: ".Name", wantErr: ErrInvalidPath},
+		{path: "Name..SKU", wantErr: ErrInvalidPath},
+		{path: "Lines[]", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := ValueOf(newPathOrder()).Lookup(tt.path)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Lookup() error = %v, want %v", err, tt.wantErr)
+				}
+				var pe *PathError
+				if !errors.As(err, &pe) {
+					t.Fatalf("Lookup() error = %v, want a *PathError", err)
+				}
+				if tt.segment != "" && pe.Segment != tt.segment {
+					t.Errorf("PathError.Segment = %q, want %q", pe.Segment, tt.segment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Lookup() error = %v", err)
+			}
+			if v, _ := got.Interface(); v != tt.want {
+				t.Errorf("Lookup() = %v, want %v", v, tt.want)
+			}
+		})
+	}
+
+	t.Run("empty path", func(t *testing.T) {
+		v := ValueOf(3)
+		if got, err := v.Lookup(""); err != nil || got.V.Int() != 3 {
+			t.Errorf("Lookup(\"\") = %v, %v, want the value itself", got, err)
+		}
+	})
+}
+
+func TestSetPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		x       any
+		wantErr error
+	}{
+		{path: "Name", x: "changed"},
+		{path: `Items["sku"].Price`, x: 9.5},
+		{path: `Items["new"]`, x: pathItem{SKU: "new"}},
+		{path: "Lines[0].SKU", x: "z"},
+		{path: "Ptr.Price", x: 3.0},
+		{path: `Meta["item"].SKU`, x: "n"},
+		{path: `Meta["tags"][0]`, x: "w"},
+		{path: "Counts[1]", x: 2},
+		{path: "Name", x: 1, wantErr: ErrWrongType},
+		{path: `Items["nope"].Price`, x: 1.0, wantErr: ErrNotFound},
+		{path: "Lines[9].SKU", x: "z", wantErr: ErrOutOfRange},
+		{path: "Lines[", x: "z", wantErr: ErrInvalidPath},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			order := newPathOrder()
+			err := ValueOf(order).SetPath(tt.path, ValueOf(tt.x))
+			if tt.wantErr != nil {
+				var pe *PathError
+				if !errors.Is(err, tt.wantErr) || !errors.As(err, &pe) {
+					t.Fatalf("SetPath() error = %v, want a *PathError wrapping %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetPath() error = %v", err)
+			}
+			got, err := ValueOf(order).Lookup(tt.path)
+			if err != nil {
+				t.Fatalf("Lookup() after SetPath error = %v", err)
+			}
+			if v, _ := got.Interface(); !DeepEqual(v, tt.x) {
+				t.Errorf("Lookup() after SetPath = %v, want %v", v, tt.x)
+			}
+		})
+	}
+}
+
+func TestSetPathRoots(t *testing.T) {
+	tests := []struct {
+		name    string
+		root    Value
+		path    string
+		x       any
+		wantErr error
+	}{
+		{name: "unaddressable struct", root: ValueOf(pathItem{}), path: "SKU", x: "v", wantErr: ErrUnaddressable},
+		{name: "map passed by value", root: ValueOf(map[string]int{}), path: `["a"]`, x: 1},
+		{name: "nil map", root: ValueOf(map[string]int(nil)), path: `["a"]`, x: 1, wantErr: ErrNilValue},
+		{name: "nil pointer", root: ValueOf((*pathItem)(nil)), path: "SKU", x: "v", wantErr: ErrNilValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.root.SetPath(tt.path, ValueOf(tt.x)); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("SetPath() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
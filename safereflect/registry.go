@@ -0,0 +1,70 @@
+package safereflect
+
+import (
+	"reflect"
+	"sync"
+)
+
+// A Registry maps names to types. It is used to refer to named types, and to dynamic types
+// that should be known by a name, when types are described with DescribeType and rebuilt with
+// TypeFromDescriptor or parsed with ParseType. A Registry is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	byName map[string]Type
+	names  map[reflect.Type]string
+}
+
+// NewRegistry returns an empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{
+		byName: make(map[string]Type),
+		names:  make(map[reflect.Type]string),
+	}
+}
+
+// Register adds t to the registry under name. Registering the same type again under the same
+// name is allowed, but a name can not be taken by two different types. A type registered under
+// several names is described by the first of them.
+func (r *Registry) Register(name string, t Type) error {
+	if t == nil || t.ReflectType() == nil {
+		return newError("safereflect.Registry.Register", ErrNilType)
+	}
+	if name == "" {
+		return argError("safereflect.Registry.Register", "non-empty name", "")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if old, ok := r.byName[name]; ok {
+		if old.ReflectType() != t.ReflectType() {
+			return typeError("safereflect.Registry.Register", old.ReflectType(), t.ReflectType())
+		}
+		return nil
+	}
+	r.byName[name] = toType(t.ReflectType())
+	if _, ok := r.names[t.ReflectType()]; !ok {
+		r.names[t.ReflectType()] = name
+	}
+	return nil
+}
+
+// Lookup returns the type registered under name. A nil Registry contains no types.
+func (r *Registry) Lookup(name string) (Type, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.byName[name]
+	return t, ok
+}
+
+// NameOf returns the name t was first registered under. A nil Registry contains no types.
+func (r *Registry) NameOf(t Type) (string, bool) {
+	if r == nil || t == nil {
+		return "", false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	name, ok := r.names[t.ReflectType()]
+	return name, ok
+}
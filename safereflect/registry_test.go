@@ -0,0 +1,51 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	duration := TypeOf(time.Duration(0))
+
+	tests := []struct {
+		name    string
+		key     string
+		typ     Type
+		wantErr error
+	}{
+		{name: "register", key: "Duration", typ: duration},
+		{name: "same type again", key: "Duration", typ: duration},
+		{name: "second name", key: "time.Duration", typ: duration},
+		{name: "name taken", key: "Duration", typ: TypeOf(time.Month(0)), wantErr: ErrWrongType},
+		{name: "empty name", key: "", typ: duration, wantErr: ErrInvalidArgument},
+		{name: "nil type", key: "Nil", typ: nil, wantErr: ErrNilType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := r.Register(tt.key, tt.typ); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Register() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if typ, ok := r.Lookup("time.Duration"); !ok || typ != duration {
+		t.Errorf("Lookup() = %v, %v, want %v", typ, ok, duration)
+	}
+	if _, ok := r.Lookup("Month"); ok {
+		t.Error("Lookup() found a type that was never registered")
+	}
+	if name, ok := r.NameOf(duration); !ok || name != "Duration" {
+		t.Errorf("NameOf() = %q, %v, want the first name, Duration", name, ok)
+	}
+
+	var nilRegistry *Registry
+	if _, ok := nilRegistry.Lookup("Duration"); ok {
+		t.Error("nil Registry Lookup() found a type")
+	}
+	if _, ok := nilRegistry.NameOf(duration); ok {
+		t.Error("nil Registry NameOf() found a name")
+	}
+}
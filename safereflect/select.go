@@ -0,0 +1,120 @@
+package safereflect
+
+import "reflect"
+
+// A SelectDir describes the communication direction of a select case.
+type SelectDir int
+
+const (
+	_             SelectDir = iota
+	SelectSend              // case Chan <- Send
+	SelectRecv              // case <-Chan:
+	SelectDefault           // default
+)
+
+// A SelectCase describes a single case in a select operation. For SelectSend, Chan is the
+// channel and Send the value to send. For SelectRecv, Chan is the channel and Send must be the
+// zero Value. For SelectDefault, both Chan and Send must be the zero Value.
+type SelectCase struct {
+	Dir  SelectDir
+	Chan Value
+	Send Value
+}
+
+// Select executes a select operation described by the list of cases. Like the Go select
+// statement, it blocks until at least one of the cases can proceed, makes a uniform
+// pseudo-random choice, and then executes that case. It returns the index of the chosen case
+// and, for a receive case, the value received and whether it was sent on the channel.
+//
+// Every case is validated before dispatching: its direction, that Chan is a non-nil channel
+// that allows that direction, and that Send is assignable to the channel's element type. A
+// select without cases, which would block forever, is rejected as well. Choosing a send case on
+// a closed channel is an error wrapping ErrClosed.
+func Select(cases []SelectCase) (chosen int, recv Value, recvOK bool, err error) {
+	rcases, err := selectCases(cases)
+	if err != nil {
+		return -1, Value{}, false, err
+	}
+	var rv reflect.Value
+	err = Try(func() error {
+		chosen, rv, recvOK = reflect.Select(rcases)
+		return nil
+	})
+	if isRuntimePanic(err, "send on closed channel") {
+		return -1, Value{}, false, newError("reflect.Select", ErrClosed)
+	}
+	if err != nil {
+		return -1, Value{}, false, err
+	}
+	return chosen, Value{rv}, recvOK, nil
+}
+
+func selectCases(cases []SelectCase) ([]reflect.SelectCase, error) {
+	if len(cases) == 0 {
+		return nil, argError("reflect.Select", "at least one case", "0")
+	}
+	out := make([]reflect.SelectCase, len(cases))
+	hasDefault := false
+	for i, c := range cases {
+		var e *ValueError
+		switch c.Dir {
+		case SelectDefault:
+			if hasDefault {
+				e = argError("reflect.Select", "at most one default case", "multiple")
+			} else if c.Chan.V.IsValid() || c.Send.V.IsValid() {
+				e = argError("reflect.Select", "default case without Chan and Send", "")
+			}
+			hasDefault = true
+			out[i] = reflect.SelectCase{Dir: reflect.SelectDefault}
+		case SelectSend:
+			e = checkSelectChan(c.Chan, reflect.SendDir)
+			if e == nil {
+				elem := c.Chan.V.Type().Elem()
+				switch {
+				case !c.Send.V.IsValid():
+					e = newError("reflect.Select", ErrInvalidValue)
+					e.Expected = elem.String()
+				case !c.Send.V.CanInterface():
+					e = newError("reflect.Select", ErrUnexported)
+				case !c.Send.V.Type().AssignableTo(elem):
+					e = typeError("reflect.Select", elem, c.Send.V.Type())
+				}
+			}
+			out[i] = reflect.SelectCase{Dir: reflect.SelectSend, Chan: c.Chan.V, Send: c.Send.V}
+		case SelectRecv:
+			e = checkSelectChan(c.Chan, reflect.RecvDir)
+			if e == nil && c.Send.V.IsValid() {
+				e = argError("reflect.Select", "receive case without Send", c.Send.V.Type().String())
+			}
+			out[i] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: c.Chan.V}
+		default:
+			e = argError("reflect.Select", "SelectSend, SelectRecv or SelectDefault", "")
+		}
+		if e != nil {
+			e.Index = i
+			return nil, e
+		}
+	}
+	return out, nil
+}
+
+func checkSelectChan(ch Value, dir reflect.ChanDir) *ValueError {
+	if !ch.V.IsValid() {
+		e := newError("reflect.Select", ErrInvalidValue)
+		e.Expected = "chan"
+		return e
+	}
+	if ch.V.Kind() != reflect.Chan {
+		return kindError("reflect.Select", "chan", ch.V.Kind())
+	}
+	if !ch.V.CanInterface() {
+		return newError("reflect.Select", ErrUnexported)
+	}
+	if ch.V.Type().ChanDir()&dir == 0 {
+		return typeError("reflect.Select", reflect.ChanOf(dir, ch.V.Type().Elem()), ch.V.Type())
+	}
+	if ch.V.IsNil() {
+		return newError("reflect.Select", ErrNilValue)
+	}
+	return nil
+}
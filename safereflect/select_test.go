@@ -0,0 +1,133 @@
+package safereflect
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	ready := make(chan int, 1)
+	ready <- 5
+	empty := make(chan int)
+	buffered := make(chan int, 1)
+
+	tests := []struct {
+		name   string
+		cases  []SelectCase
+		chosen int
+		recv   any
+		recvOK bool
+	}{
+		{
+			name:   "ready receive",
+			cases:  []SelectCase{{Dir: SelectRecv, Chan: ValueOf(empty)}, {Dir: SelectRecv, Chan: ValueOf(ready)}},
+			chosen: 1,
+			recv:   5,
+			recvOK: true,
+		},
+		{
+			name:   "default when nothing is ready",
+			cases:  []SelectCase{{Dir: SelectRecv, Chan: ValueOf(empty)}, {Dir: SelectDefault}},
+			chosen: 1,
+		},
+		{
+			name:   "ready send",
+			cases:  []SelectCase{{Dir: SelectSend, Chan: ValueOf(buffered), Send: ValueOf(3)}, {Dir: SelectDefault}},
+			chosen: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chosen, recv, recvOK, err := Select(tt.cases)
+			if err != nil {
+				t.Fatalf("Select() error = %v", err)
+			}
+			if chosen != tt.chosen || recvOK != tt.recvOK {
+				t.Fatalf("Select() = %d, %v, want %d, %v", chosen, recvOK, tt.chosen, tt.recvOK)
+			}
+			if tt.recv != nil {
+				if got, _ := recv.Interface(); got != tt.recv {
+					t.Errorf("received %v, want %v", got, tt.recv)
+				}
+			}
+		})
+	}
+}
+
+func TestSelectRejects(t *testing.T) {
+	ch := make(chan int)
+	var recvOnly <-chan int = ch
+	var sendOnly chan<- int = ch
+	var nilChan chan int
+	closed := make(chan int)
+	close(closed)
+
+	tests := []struct {
+		name    string
+		cases   []SelectCase
+		index   int
+		wantErr error
+	}{
+		{name: "no cases", cases: nil, index: -1, wantErr: ErrInvalidArgument},
+		{name: "unknown direction", cases: []SelectCase{{Chan: ValueOf(ch)}}, wantErr: ErrInvalidArgument},
+		{name: "two defaults", cases: []SelectCase{{Dir: SelectDefault}, {Dir: SelectDefault}}, index: 1, wantErr: ErrInvalidArgument},
+		{name: "default with channel", cases: []SelectCase{{Dir: SelectDefault, Chan: ValueOf(ch)}}, wantErr: ErrInvalidArgument},
+		{name: "receive from zero Value", cases: []SelectCase{{Dir: SelectRecv}}, wantErr: ErrInvalidValue},
+		{name: "receive from non-channel", cases: []SelectCase{{Dir: SelectRecv, Chan: ValueOf(1)}}, wantErr: ErrWrongKind},
+		{name: "receive from send-only", cases: []SelectCase{{Dir: SelectRecv, Chan: ValueOf(sendOnly)}}, wantErr: ErrWrongType},
+		{name: "receive with Send", cases: []SelectCase{{Dir: SelectRecv, Chan: ValueOf(ch), Send: ValueOf(1)}}, wantErr: ErrInvalidArgument},
+		{name: "receive from nil channel", cases: []SelectCase{{Dir: SelectDefault}, {Dir: SelectRecv, Chan: ValueOf(nilChan)}}, index: 1, wantErr: ErrNilValue},
+		{name: "send on receive-only", cases: []SelectCase{{Dir: SelectSend, Chan: ValueOf(recvOnly), Send: ValueOf(1)}}, wantErr: ErrWrongType},
+		{name: "send without value", cases: []SelectCase{{Dir: SelectSend, Chan: ValueOf(ch)}}, wantErr: ErrInvalidValue},
+		{name: "send of wrong type", cases: []SelectCase{{Dir: SelectSend, Chan: ValueOf(ch), Send: ValueOf("x")}}, wantErr: ErrWrongType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chosen, _, _, err := Select(tt.cases)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Select() error = %v, want %v", err, tt.wantErr)
+			}
+			if chosen != -1 {
+				t.Errorf("chosen = %d, want -1", chosen)
+			}
+			var ve *ValueError
+			if errors.As(err, &ve) && ve.Index != tt.index {
+				t.Errorf("ValueError.Index = %d, want %d", ve.Index, tt.index)
+			}
+		})
+	}
+
+	t.Run("send on closed channel", func(t *testing.T) {
+		_, _, _, err := Select([]SelectCase{{Dir: SelectSend, Chan: ValueOf(closed), Send: ValueOf(1)}})
+		if !errors.Is(err, ErrClosed) {
+			t.Fatalf("Select() error = %v, want %v", err, ErrClosed)
+		}
+		var ve *ValueError
+		if !errors.As(err, &ve) || ve.Method != "reflect.Select" {
+			t.Errorf("Select() error = %#v, want a *ValueError from reflect.Select", err)
+		}
+	})
+}
+
+func TestClose(t *testing.T) {
+	var recvOnly <-chan int = make(chan int)
+	var nilChan chan int
+
+	tests := []struct {
+		name    string
+		v       Value
+		wantErr error
+	}{
+		{name: "open channel", v: ValueOf(make(chan int))},
+		{name: "not a channel", v: ValueOf(1), wantErr: ErrWrongKind},
+		{name: "receive-only channel", v: ValueOf(recvOnly), wantErr: ErrWrongType},
+		{name: "nil channel", v: ValueOf(nilChan), wantErr: ErrNilValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.v.Close(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Close() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package safereflect
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// A SetMode selects the rule used to store a value with Value.SetWithMode.
+type SetMode int
+
+const (
+	// SetModeExact requires x to have exactly the type of v, as Value.Set does.
+	SetModeExact SetMode = iota
+	// SetModeAssignable follows Go assignability rules, as Value.SetAssignable does.
+	SetModeAssignable
+	// SetModeConvert converts x to the type of v when needed, as Value.SetConvert does.
+	SetModeConvert
+)
+
+func (m SetMode) String() string {
+	switch m {
+	case SetModeExact:
+		return "exact"
+	case SetModeAssignable:
+		return "assignable"
+	case SetModeConvert:
+		return "convert"
+	}
+	return "SetMode(" + strconv.Itoa(int(m)) + ")"
+}
+
+// A SetOption configures the helpers in gendynamic and refractutils that store values, such as
+// gendynamic.SetStructFieldValue and refractutils.SetSliceIndex.
+type SetOption func(*SetConfig)
+
+// SetConfig holds the settings selected by a list of SetOptions. See ApplySetOptions.
+type SetConfig struct {
+	// Mode is the rule used to store values. The default is SetModeExact.
+	Mode SetMode
+}
+
+// WithSetMode selects how a value is stored into its destination. The default, SetModeExact,
+// requires the value to have exactly the destination type. SetModeAssignable also accepts
+// values that are assignable to it, such as a *bytes.Buffer for an io.Reader, and
+// SetModeConvert converts the value as long as nothing is lost, such as an int for an int64.
+func WithSetMode(mode SetMode) SetOption {
+	return func(c *SetConfig) {
+		c.Mode = mode
+	}
+}
+
+// ApplySetOptions returns the settings selected by opts, for use by functions that accept
+// SetOptions.
+func ApplySetOptions(opts ...SetOption) SetConfig {
+	var c SetConfig
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// SetWithMode assigns x to v using the rule selected by mode.
+func (v Value) SetWithMode(x Value, mode SetMode) error {
+	switch mode {
+	case SetModeExact:
+		return v.Set(x)
+	case SetModeAssignable:
+		return v.SetAssignable(x)
+	case SetModeConvert:
+		return v.SetConvert(x)
+	}
+	return argError("reflect.Value.SetWithMode", "exact, assignable or convert mode", mode.String())
+}
+
+// SetAssignable assigns x to v following the Go assignability rules, so a *bytes.Buffer can be
+// stored in an io.Reader and a value of a named type in a variable of its unnamed underlying
+// type. v must be settable and x must be usable without access to unexported fields.
+func (v Value) SetAssignable(x Value) error {
+	if err := checkSetSource("reflect.Value.SetAssignable", v, x); err != nil {
+		return err
+	}
+	if !x.V.Type().AssignableTo(v.V.Type()) {
+		return typeError("reflect.Value.SetAssignable", v.V.Type(), x.V.Type())
+	}
+	v.V.Set(x.V)
+	return nil
+}
+
+// SetConvert assigns x to v, converting it to the type of v when it is not directly assignable.
+// Only conversions that keep the value intact are performed: numbers are converted following the
+// rules of ConvertNumeric, so 300 into an int8, -1 into a uint or 1.5 into an int are rejected
+// with the errors described there, and converting an integer to a string, which yields the UTF-8
+// encoding of a rune rather than its decimal form, is rejected too. Converting between numeric
+// kinds that Go does not convert directly, such as a complex number with a zero imaginary part
+// to a float, is allowed as well.
+func (v Value) SetConvert(x Value) error {
+	if err := checkSetSource("reflect.Value.SetConvert", v, x); err != nil {
+		return err
+	}
+	vt, xt := v.V.Type(), x.V.Type()
+	if xt.AssignableTo(vt) {
+		v.V.Set(x.V)
+		return nil
+	}
+	if isNumericKind(xt.Kind()) && isNumericKind(vt.Kind()) {
+		out, err := convertNumeric(x.V, vt)
+		if err != nil {
+			e := typeError("reflect.Value.SetConvert", vt, xt)
+			e.Err = err
+			return e
+		}
+		v.V.Set(out)
+		return nil
+	}
+	if !x.V.CanConvert(vt) || (isNumericKind(xt.Kind()) && vt.Kind() == reflect.String) {
+		return typeError("reflect.Value.SetConvert", vt, xt)
+	}
+	v.V.Set(x.V.Convert(vt))
+	return nil
+}
+
+func checkSetSource(method string, v, x Value) *ValueError {
+	if !v.CanSet() {
+		return newError(method, ErrUnaddressable)
+	}
+	if !x.V.IsValid() {
+		e := newError(method, ErrInvalidValue)
+		e.Expected = v.V.Type().String()
+		return e
+	}
+	if !x.V.CanInterface() {
+		return newError(method, ErrUnexported)
+	}
+	return nil
+}
+
+func isNumericKind(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Complex128
+}
@@ -0,0 +1,91 @@
+package safereflect
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+type celsius float64
+
+func TestSetWithMode(t *testing.T) {
+	tests := []struct {
+		name    string
+		dst     any // pointer to the destination
+		x       any
+		mode    SetMode
+		want    any
+		wantErr error
+	}{
+		{name: "exact", dst: new(int), x: 1, mode: SetModeExact, want: 1},
+		{name: "exact rejects assignable", dst: new(io.Reader), x: &bytes.Buffer{}, mode: SetModeExact, wantErr: ErrWrongType},
+		{name: "assignable interface", dst: new(io.Reader), x: bytes.NewBufferString("a"), mode: SetModeAssignable, want: bytes.NewBufferString("a")},
+		{name: "assignable rejects conversion", dst: new(int64), x: 1, mode: SetModeAssignable, wantErr: ErrWrongType},
+		{name: "convert widening", dst: new(int64), x: 1, mode: SetModeConvert, want: int64(1)},
+		{name: "convert named float", dst: new(celsius), x: 21.5, mode: SetModeConvert, want: celsius(21.5)},
+		{name: "convert named duration", dst: new(time.Duration), x: int64(5), mode: SetModeConvert, want: time.Duration(5)},
+		{name: "convert complex to float", dst: new(float64), x: complex(2, 0), mode: SetModeConvert, want: 2.0},
+		{name: "convert overflow", dst: new(int8), x: 300, mode: SetModeConvert, wantErr: ErrOverflow},
+		{name: "convert sign loss", dst: new(uint), x: -1, mode: SetModeConvert, wantErr: ErrSignLoss},
+		{name: "convert truncation", dst: new(int), x: 1.5, mode: SetModeConvert, wantErr: ErrTruncated},
+		{name: "convert int to string", dst: new(string), x: 65, mode: SetModeConvert, wantErr: ErrWrongType},
+		{name: "convert string to bytes", dst: new([]byte), x: "ab", mode: SetModeConvert, want: []byte("ab")},
+		{name: "convert unrelated", dst: new(int), x: "1", mode: SetModeConvert, wantErr: ErrWrongType},
+		{name: "unknown mode", dst: new(int), x: 1, mode: SetMode(9), wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := mustValueOf(tt.dst)
+			err := dst.SetWithMode(ValueOf(tt.x), tt.mode)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("SetWithMode() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetWithMode() error = %v", err)
+			}
+			if got, _ := dst.Interface(); !DeepEqual(got, tt.want) {
+				t.Errorf("destination = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetSourceChecks(t *testing.T) {
+	var unexported struct{ n int }
+	tests := []struct {
+		name    string
+		dst     Value
+		x       Value
+		wantErr error
+	}{
+		{name: "unaddressable", dst: ValueOf(1), x: ValueOf(2), wantErr: ErrUnaddressable},
+		{name: "zero Value", dst: mustValueOf(new(int)), x: Value{}, wantErr: ErrInvalidValue},
+		{name: "unexported source", dst: mustValueOf(new(int)), x: Value{ValueOf(unexported).V.Field(0)}, wantErr: ErrUnexported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, mode := range []SetMode{SetModeAssignable, SetModeConvert} {
+				if err := tt.dst.SetWithMode(tt.x, mode); !errors.Is(err, tt.wantErr) {
+					t.Errorf("SetWithMode(%v) error = %v, want %v", mode, err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestApplySetOptions(t *testing.T) {
+	if got := ApplySetOptions().Mode; got != SetModeExact {
+		t.Errorf("default mode = %v, want %v", got, SetModeExact)
+	}
+	if got := ApplySetOptions(WithSetMode(SetModeAssignable), WithSetMode(SetModeConvert)).Mode; got != SetModeConvert {
+		t.Errorf("mode = %v, want the last option, %v", got, SetModeConvert)
+	}
+	if got := SetMode(7).String(); got != "SetMode(7)" {
+		t.Errorf("String() = %s, want SetMode(7)", got)
+	}
+}
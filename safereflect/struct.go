@@ -23,6 +23,18 @@ func (f StructField) IsExported() bool {
 	return f.PkgPath == ""
 }
 
+func toStructField(f reflect.StructField) StructField {
+	return StructField{
+		Name:      f.Name,
+		PkgPath:   f.PkgPath,
+		Type:      toType(f.Type),
+		Tag:       StructTag(f.Tag),
+		Offset:    f.Offset,
+		Index:     f.Index,
+		Anonymous: f.Anonymous,
+	}
+}
+
 type StructTag string
 
 func (tag StructTag) Get(key string) (value string) {
@@ -0,0 +1,169 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+)
+
+// StructFieldError describes a field that StructOf rejected. StructOf reports every rejected
+// field at once, joined with errors.Join, so each of them can be retrieved with errors.As or by
+// unwrapping the joined error.
+type StructFieldError struct {
+	Index  int    // position of the field in the slice passed to StructOf
+	Name   string // name of the field, possibly empty
+	Reason string // why the field was rejected
+	Err    error  // one of the sentinel errors
+}
+
+func (e *StructFieldError) Error() string {
+	return fmt.Sprintf("reflect.StructOf: field %d (%q) %s", e.Index, e.Name, e.Reason)
+}
+
+func (e *StructFieldError) Unwrap() error {
+	return e.Err
+}
+
+// validateStructFields checks fields against every rule that reflect.StructOf enforces by
+// panicking and returns all violations joined.
+func validateStructFields(fields []StructField) error {
+	var errs []error
+	fail := func(i int, err error, format string, args ...any) {
+		errs = append(errs, &StructFieldError{Index: i, Name: fields[i].Name, Reason: fmt.Sprintf(format, args...), Err: err})
+	}
+	names := make(map[string]int, len(fields))
+	pkgPath, pkgPathField := "", -1
+	var size uintptr
+	for i, field := range fields {
+		switch {
+		case field.Name == "":
+			fail(i, ErrInvalidArgument, "has no name")
+		case !isValidFieldName(field.Name):
+			fail(i, ErrInvalidArgument, "has invalid name, must be a Go identifier")
+		default:
+			if j, dup := names[field.Name]; dup && field.Name != "_" {
+				fail(i, ErrInvalidArgument, "duplicates the name of field %d", j)
+			} else {
+				names[field.Name] = i
+			}
+		}
+		if field.Anonymous && field.PkgPath != "" {
+			fail(i, ErrInvalidArgument, "is embedded but has PkgPath %q set", field.PkgPath)
+		}
+		if field.PkgPath == "" && field.Name != "" {
+			if c := field.Name[0]; 'a' <= c && c <= 'z' || c == '_' {
+				fail(i, ErrInvalidArgument, "is unexported but missing PkgPath")
+			}
+		}
+		if field.PkgPath != "" {
+			if pkgPathField < 0 {
+				pkgPath, pkgPathField = field.PkgPath, i
+			} else if field.PkgPath != pkgPath {
+				fail(i, ErrInvalidArgument, "has PkgPath %q, but field %d has PkgPath %q", field.PkgPath, pkgPathField, pkgPath)
+			}
+		}
+		if field.Type == nil || field.Type.ReflectType() == nil {
+			fail(i, ErrNilType, "has no type")
+			continue
+		}
+		ft := field.Type.ReflectType()
+		if field.Anonymous {
+			if reason := checkEmbedded(ft, i, len(fields)); reason != "" {
+				fail(i, ErrWrongType, "%s", reason)
+			}
+		}
+		offset := align(size, uintptr(ft.Align()))
+		if offset < size || offset+ft.Size() < offset {
+			fail(i, ErrInvalidArgument, "makes the struct size exceed the virtual address space")
+			break
+		}
+		size = offset + ft.Size()
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateStructTags checks that the tags of fields follow the conventional
+// `key:"value" key:"value"` syntax, with no key repeated, as StructTag.Parse does. StructOf
+// accepts any tag, like reflect.StructOf, so callers that build types from user supplied schemas
+// can call ValidateStructTags first to reject tags that StructTag.Get would silently misread.
+// Every malformed tag is reported as a *StructFieldError wrapping ErrInvalidTag, joined with
+// errors.Join.
+func ValidateStructTags(fields []StructField) error {
+	var errs []error
+	for i, field := range fields {
+		if _, err := parseTag(field.Tag); err != nil {
+			errs = append(errs, &StructFieldError{Index: i, Name: field.Name, Reason: "has malformed tag: " + err.Error(), Err: ErrInvalidTag})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkEmbedded mirrors the restrictions reflect.StructOf places on embedded fields and returns
+// a description of the first one ft violates, or "" if there is none. Unexported methods of
+// non-interface types can not be seen through reflect, so that case is left to Try.
+func checkEmbedded(ft reflect.Type, i, numFields int) string {
+	switch ft.Kind() {
+	case reflect.Interface:
+		for m := 0; m < ft.NumMethod(); m++ {
+			if ft.Method(m).PkgPath != "" {
+				return "embeds interface " + ft.String() + " with unexported methods, which is not supported"
+			}
+		}
+	case reflect.Pointer:
+		if k := ft.Elem().Kind(); k == reflect.Pointer || k == reflect.Interface {
+			return "has illegal embedded type " + ft.String()
+		}
+		if ft.NumMethod() > 0 {
+			if i > 0 {
+				return "embeds " + ft.String() + " which has methods, and must therefore be the first field"
+			}
+			if numFields > 1 {
+				return "embeds " + ft.String() + " which has methods, and must therefore be the only field"
+			}
+		}
+	default:
+		if ft.NumMethod() > 0 && i > 0 {
+			return "embeds " + ft.String() + " which has methods, and must therefore be the first field"
+		}
+		if (ft.Name() != "" || ft.NumMethod() > 0) && numFields > 1 && isPointerShaped(ft) {
+			return "embeds pointer-shaped type " + ft.String() + ", which is only supported as the only field"
+		}
+	}
+	return ""
+}
+
+// isPointerShaped reports whether values of t are stored directly in an interface word.
+func isPointerShaped(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Chan, reflect.Map, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return t.NumField() == 1 && isPointerShaped(t.Field(0).Type)
+	case reflect.Array:
+		return t.Len() == 1 && isPointerShaped(t.Elem())
+	}
+	return false
+}
+
+// isValidFieldName reports whether name is a Go identifier, using the same rules as reflect.
+func isValidFieldName(name string) bool {
+	for i, c := range name {
+		letter := 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || c == '_' || c >= utf8.RuneSelf && unicode.IsLetter(c)
+		if i == 0 && !letter {
+			return false
+		}
+		if !letter && !unicode.IsDigit(c) {
+			return false
+		}
+	}
+	return len(name) > 0
+}
+
+func align(x, a uintptr) uintptr {
+	if a == 0 {
+		return x
+	}
+	return (x + a - 1) &^ (a - 1)
+}
@@ -0,0 +1,133 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+type Marker struct{}
+
+func (Marker) M() {}
+
+func TestStructOfRejections(t *testing.T) {
+	intType := TypeOf(0)
+	tests := []struct {
+		name    string
+		fields  []StructField
+		invalid []int // indexes of the rejected fields
+	}{
+		{name: "no name", fields: []StructField{{Type: intType}}, invalid: []int{0}},
+		{name: "invalid identifier", fields: []StructField{{Name: "1A", Type: intType}}, invalid: []int{0}},
+		{name: "duplicate name", fields: []StructField{{Name: "A", Type: intType}, {Name: "A", Type: intType}}, invalid: []int{1}},
+		{name: "unexported without PkgPath", fields: []StructField{{Name: "a", Type: intType}}, invalid: []int{0}},
+		{name: "nil type", fields: []StructField{{Name: "A"}}, invalid: []int{0}},
+		{name: "embedded with PkgPath", fields: []StructField{{Name: "A", PkgPath: "p", Type: intType, Anonymous: true}}, invalid: []int{0}},
+		{
+			name:    "embedded type with methods not first",
+			fields:  []StructField{{Name: "A", Type: intType}, {Name: "Marker", Type: TypeOf(Marker{}), Anonymous: true}},
+			invalid: []int{1},
+		},
+		{
+			name:    "embedded pointer to pointer",
+			fields:  []StructField{{Name: "P", Type: TypeOf((**int)(nil)), Anonymous: true}},
+			invalid: []int{0},
+		},
+		{
+			name:    "several violations",
+			fields:  []StructField{{Name: "", Type: intType}, {Name: "B", Type: intType}, {Name: "c", Type: intType}},
+			invalid: []int{0, 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := StructOf(tt.fields)
+			if err == nil {
+				t.Fatalf("StructOf() = %v, want an error", typ)
+			}
+			var got []int
+			for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+				var fe *StructFieldError
+				if !errors.As(e, &fe) {
+					t.Fatalf("error %v is not a *StructFieldError", e)
+				}
+				got = append(got, fe.Index)
+			}
+			if fmt.Sprint(got) != fmt.Sprint(tt.invalid) {
+				t.Errorf("rejected fields %v, want %v (error: %v)", got, tt.invalid, err)
+			}
+		})
+	}
+}
+
+func TestStructOfAccepts(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields []StructField
+		want   string
+	}{
+		{
+			name:   "exported fields with tags",
+			fields: []StructField{{Name: "A", Type: TypeOf(0), Tag: `json:"a"`}, {Name: "B", Type: TypeOf("")}},
+			want:   "struct { A int \"json:\\\"a\\\"\"; B string }",
+		},
+		{
+			name:   "unexported field with PkgPath",
+			fields: []StructField{{Name: "a", PkgPath: "example.com/p", Type: TypeOf(0)}},
+			want:   "struct { a int }",
+		},
+		{
+			name:   "malformed tag, as reflect.StructOf allows",
+			fields: []StructField{{Name: "A", Type: TypeOf(0), Tag: `json:"a`}},
+			want:   "struct { A int \"json:\\\"a\" }",
+		},
+		{
+			name:   "repeated tag key",
+			fields: []StructField{{Name: "A", Type: TypeOf(0), Tag: `json:"a" json:"b"`}},
+			want:   "struct { A int \"json:\\\"a\\\" json:\\\"b\\\"\" }",
+		},
+		{
+			name:   "blank fields",
+			fields: []StructField{{Name: "_", PkgPath: "example.com/p", Type: TypeOf(0)}, {Name: "_", PkgPath: "example.com/p", Type: TypeOf(0)}},
+			want:   "struct { _ int; _ int }",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typ, err := StructOf(tt.fields)
+			if err != nil {
+				t.Fatalf("StructOf() error = %v", err)
+			}
+			if got := typ.String(); got != tt.want {
+				t.Errorf("StructOf() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateStructTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tag     StructTag
+		wantErr bool
+	}{
+		{name: "empty", tag: ``},
+		{name: "single key", tag: `json:"a,omitempty"`},
+		{name: "several keys", tag: `json:"a" yaml:"b"`},
+		{name: "unterminated value", tag: `json:"a`, wantErr: true},
+		{name: "repeated key", tag: `json:"a" json:"b"`, wantErr: true},
+		{name: "missing quotes", tag: `json:a`, wantErr: true},
+		{name: "missing space", tag: `json:"a"yaml:"b"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStructTags([]StructField{{Name: "A", Type: TypeOf(0), Tag: tt.tag}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateStructTags() error = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTag) {
+				t.Errorf("ValidateStructTags() error = %v, want %v", err, ErrInvalidTag)
+			}
+		})
+	}
+}
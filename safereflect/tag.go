@@ -0,0 +1,163 @@
+package safereflect
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// A TagEntry is a single key:"value" pair of a struct tag. By convention the value is a name
+// followed by comma separated options, so `json:"id,omitempty,string"` has the Name "id" and the
+// Options omitempty and string.
+type TagEntry struct {
+	Key     string
+	Value   string
+	Name    string
+	Options []string
+}
+
+// HasOption reports whether option is one of the options of e.
+func (e TagEntry) HasOption(option string) bool {
+	for _, o := range e.Options {
+		if o == option {
+			return true
+		}
+	}
+	return false
+}
+
+func (e TagEntry) String() string {
+	return e.Key + ":" + strconv.Quote(e.Value)
+}
+
+// Parse returns the entries of tag in the order they appear. Unlike Get and Lookup, which
+// silently stop at the first malformed pair, Parse requires tag to follow the conventional
+// `key:"value" key:"value"` syntax exactly, with no key repeated, and returns an error wrapping
+// ErrInvalidTag describing the first violation.
+func (tag StructTag) Parse() ([]TagEntry, error) {
+	entries, err := parseTag(tag)
+	if err != nil {
+		return nil, fmt.Errorf("reflect.StructTag.Parse: %w: %w", ErrInvalidTag, err)
+	}
+	return entries, nil
+}
+
+// Set returns tag with key set to value. An existing entry for key is replaced in place, and a
+// new one is appended. The returned tag is formatted canonically, with entries separated by a
+// single space. It is an error if tag is malformed or key is not a valid tag key.
+func (tag StructTag) Set(key, value string) (StructTag, error) {
+	if !isValidTagKey(key) {
+		return tag, argError("reflect.StructTag.Set", "valid tag key", strconv.Quote(key))
+	}
+	entries, err := tag.Parse()
+	if err != nil {
+		return tag, err
+	}
+	entry := newTagEntry(key, value)
+	for i, e := range entries {
+		if e.Key == key {
+			entries[i] = entry
+			return formatTag(entries), nil
+		}
+	}
+	return formatTag(append(entries, entry)), nil
+}
+
+// Delete returns tag without the entry for key, formatted as by Set. Deleting a key that is not
+// present is not an error.
+func (tag StructTag) Delete(key string) (StructTag, error) {
+	entries, err := tag.Parse()
+	if err != nil {
+		return tag, err
+	}
+	out := entries[:0]
+	for _, e := range entries {
+		if e.Key != key {
+			out = append(out, e)
+		}
+	}
+	return formatTag(out), nil
+}
+
+func newTagEntry(key, value string) TagEntry {
+	name, opts, _ := strings.Cut(value, ",")
+	e := TagEntry{Key: key, Value: value, Name: name}
+	if opts != "" {
+		e.Options = strings.Split(opts, ",")
+	}
+	return e
+}
+
+func formatTag(entries []TagEntry) StructTag {
+	parts := make([]string, len(entries))
+	for i, e := range entries {
+		parts[i] = e.String()
+	}
+	return StructTag(strings.Join(parts, " "))
+}
+
+func isValidTagKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == ':' || key[i] == '"' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// parseTag splits tag into its entries, checking that it follows the conventional
+// `key:"value" key:"value"` syntax that reflect.StructTag.Get expects and that no key is
+// repeated.
+func parseTag(tag StructTag) ([]TagEntry, error) {
+	var entries []TagEntry
+	seen := make(map[string]bool)
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 {
+			return nil, fmt.Errorf("expected key at %q", tag)
+		}
+		key := string(tag[:i])
+		if i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, fmt.Errorf("key %q is not followed by :\"value\"", key)
+		}
+		tag = tag[i+1:]
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("value of key %q is not terminated", key)
+		}
+		value, err := strconv.Unquote(string(tag[:i+1]))
+		if err != nil {
+			return nil, fmt.Errorf("value of key %q is not a valid quoted string", key)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("key %q is repeated", key)
+		}
+		seen[key] = true
+		entries = append(entries, newTagEntry(key, value))
+		tag = tag[i+1:]
+		if tag != "" && tag[0] != ' ' {
+			return nil, fmt.Errorf("key %q is not followed by a space", key)
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,92 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestStructTagParse(t *testing.T) {
+	tests := []struct {
+		tag     StructTag
+		want    []TagEntry
+		wantErr bool
+	}{
+		{tag: ``},
+		{tag: `  `},
+		{
+			tag:  `json:"id,omitempty,string" yaml:"id"`,
+			want: []TagEntry{{Key: "json", Value: "id,omitempty,string", Name: "id", Options: []string{"omitempty", "string"}}, {Key: "yaml", Value: "id", Name: "id"}},
+		},
+		{tag: `json:",omitempty"`, want: []TagEntry{{Key: "json", Value: ",omitempty", Options: []string{"omitempty"}}}},
+		{tag: `x:"a \"q\""`, want: []TagEntry{{Key: "x", Value: `a "q"`, Name: `a "q"`}}},
+		{tag: `json:"a`, wantErr: true},
+		{tag: `json:a`, wantErr: true},
+		{tag: `json "a"`, wantErr: true},
+		{tag: `:"a"`, wantErr: true},
+		{tag: `json:"a"yaml:"b"`, wantErr: true},
+		{tag: `json:"a" json:"b"`, wantErr: true},
+		{tag: `x:"\z"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.tag), func(t *testing.T) {
+			got, err := tt.tag.Parse()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidTag) {
+					t.Fatalf("Parse() error = %v, want %v", err, ErrInvalidTag)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStructTagSetDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		edit    func() (StructTag, error)
+		want    StructTag
+		wantErr error
+	}{
+		{name: "set new key", edit: func() (StructTag, error) { return StructTag(`json:"a"`).Set("yaml", "b") }, want: `json:"a" yaml:"b"`},
+		{name: "set on empty tag", edit: func() (StructTag, error) { return StructTag(``).Set("json", "a") }, want: `json:"a"`},
+		{name: "replace in place", edit: func() (StructTag, error) { return StructTag(`json:"a" yaml:"b"`).Set("json", "c") }, want: `json:"c" yaml:"b"`},
+		{name: "canonical spacing", edit: func() (StructTag, error) { return StructTag(`json:"a"   yaml:"b"`).Set("db", "c") }, want: `json:"a" yaml:"b" db:"c"`},
+		{name: "value is quoted", edit: func() (StructTag, error) { return StructTag(``).Set("x", `a"b`) }, want: `x:"a\"b"`},
+		{name: "invalid key", edit: func() (StructTag, error) { return StructTag(``).Set("a b", "c") }, wantErr: ErrInvalidArgument},
+		{name: "set on malformed tag", edit: func() (StructTag, error) { return StructTag(`json:"a`).Set("yaml", "b") }, wantErr: ErrInvalidTag},
+		{name: "delete", edit: func() (StructTag, error) { return StructTag(`json:"a" yaml:"b"`).Delete("json") }, want: `yaml:"b"`},
+		{name: "delete missing key", edit: func() (StructTag, error) { return StructTag(`json:"a"`).Delete("yaml") }, want: `json:"a"`},
+		{name: "delete on malformed tag", edit: func() (StructTag, error) { return StructTag(`json:a`).Delete("json") }, wantErr: ErrInvalidTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.edit()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("tag = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTagEntryHasOption(t *testing.T) {
+	e := newTagEntry("json", "id,omitempty")
+	if !e.HasOption("omitempty") || e.HasOption("string") || e.HasOption("id") {
+		t.Errorf("HasOption() gives wrong results for %#v", e)
+	}
+}
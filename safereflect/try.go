@@ -0,0 +1,52 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// PanicError is returned by Try when f panics. Value is the value that was passed to panic
+// and Stack is the stack trace of the goroutine at the time the panic was recovered.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("safereflect: recovered panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value if it is an error, so that runtime errors such as
+// runtime.Error can be matched with errors.As.
+func (e *PanicError) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// Try calls f and returns its error. If f panics, the panic is recovered and returned as a
+// *PanicError instead. Try is used internally around the reflect calls that can not be fully
+// validated up front, and can be used to guard user code in the same way.
+func Try(f func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = &PanicError{Value: r, Stack: debug.Stack()}
+		}
+	}()
+	return f()
+}
+
+// isRuntimePanic reports whether err is a *PanicError recovered from the runtime error with the
+// given message, such as "send on closed channel". Any other panic is left for the caller to
+// return as it is.
+func isRuntimePanic(err error, msg string) bool {
+	var pe *PanicError
+	if !errors.As(err, &pe) {
+		return false
+	}
+	re, ok := pe.Value.(runtime.Error)
+	return ok && re.Error() == msg
+}
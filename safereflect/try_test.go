@@ -0,0 +1,118 @@
+package safereflect
+
+import (
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestTry(t *testing.T) {
+	errPlain := errors.New("plain")
+	tests := []struct {
+		name      string
+		f         func() error
+		wantErr   error
+		wantPanic any
+	}{
+		{name: "no error", f: func() error { return nil }},
+		{name: "returned error", f: func() error { return errPlain }, wantErr: errPlain},
+		{name: "panic with string", f: func() error { panic("boom") }, wantPanic: "boom"},
+		{name: "panic with error", f: func() error { panic(errPlain) }, wantErr: errPlain, wantPanic: errPlain},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Try(tt.f)
+			if tt.wantErr == nil && tt.wantPanic == nil {
+				if err != nil {
+					t.Fatalf("Try() = %v, want nil", err)
+				}
+				return
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Try() = %v, want %v", err, tt.wantErr)
+			}
+			var pe *PanicError
+			if got := errors.As(err, &pe); got != (tt.wantPanic != nil) {
+				t.Fatalf("Try() = %v, PanicError %v, want %v", err, got, tt.wantPanic != nil)
+			}
+			if pe != nil {
+				if pe.Value != tt.wantPanic {
+					t.Errorf("PanicError.Value = %v, want %v", pe.Value, tt.wantPanic)
+				}
+				if len(pe.Stack) == 0 {
+					t.Error("PanicError.Stack is empty")
+				}
+			}
+		})
+	}
+}
+
+func TestTryRuntimeError(t *testing.T) {
+	err := Try(func() error {
+		var m map[string]int
+		m["a"] = 1
+		return nil
+	})
+	var re runtime.Error
+	if !errors.As(err, &re) {
+		t.Fatalf("Try() = %v, want a runtime.Error", err)
+	}
+}
+
+func TestIsRuntimePanic(t *testing.T) {
+	closed := make(chan int)
+	close(closed)
+	send := Try(func() error { closed <- 1; return nil })
+	nilMap := Try(func() error {
+		var m map[string]int
+		m["a"] = 1
+		return nil
+	})
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "matching runtime error", err: send, want: true},
+		{name: "other runtime error", err: nilMap},
+		{name: "panic with the same text", err: Try(func() error { panic("send on closed channel") })},
+		{name: "returned error", err: errors.New("send on closed channel")},
+		{name: "nil", err: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRuntimePanic(tt.err, "send on closed channel"); got != tt.want {
+				t.Errorf("isRuntimePanic(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGuardedCalls(t *testing.T) {
+	closed := make(chan int)
+	close(closed)
+	hidden := ValueOf(struct{ n int }{1})
+	unexported, _ := hidden.Field(0)
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{name: "Send on closed channel", call: func() error { return ValueOf(closed).Send(ValueOf(1)) }, wantErr: ErrClosed},
+		{name: "TrySend on closed channel", call: func() error { _, err := ValueOf(closed).TrySend(ValueOf(1)); return err }, wantErr: ErrClosed},
+		{name: "Send of unexported value", call: func() error { return ValueOf(make(chan int, 1)).Send(unexported) }, wantErr: ErrUnexported},
+		{name: "TrySend of unexported value", call: func() error { _, err := ValueOf(make(chan int, 1)).TrySend(unexported); return err }, wantErr: ErrUnexported},
+		{name: "Send of zero Value", call: func() error { return ValueOf(closed).Send(Value{}) }, wantErr: ErrInvalidValue},
+		{name: "Close of closed channel", call: func() error { return ValueOf(closed).Close() }, wantErr: ErrClosed},
+		{name: "Call of panicking function", call: func() error { _, err := ValueOf(func() { panic(ErrNotFound) }).Call(nil); return err }, wantErr: ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v instead of a panic", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,9 +1,11 @@
 package safereflect
 
 import (
-	"errors"
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
 )
 
 type ChanDir int
@@ -37,6 +39,8 @@ type Type interface {
 	FieldByIndex(index []int) (StructField, error)
 	FieldByName(name string) (StructField, bool)
 	FieldByNameFunc(match func(string) bool) (StructField, bool)
+	VisibleFields() ([]StructField, error)
+	LookupField(name string) (StructField, error)
 	In(i int) (Type, error)
 	Key() (Type, error)
 	Len() (int, error)
@@ -51,8 +55,44 @@ type RefractType struct {
 	T reflect.Type
 }
 
+// TypeOf returns the Type of the dynamic value in i. Types are interned: every call for the same
+// underlying reflect.Type returns the same Type, so Types can be compared with == and used as
+// map keys.
+//
+// The intern table is never pruned. This costs nothing for the types of a program, which
+// reflect keeps alive anyway, but every type created at run time with StructOf, ParseType,
+// TypeFromDescriptor and the other constructors stays in memory for the life of the process,
+// as it does in reflect's own cache of such types. Programs that build an unbounded number of
+// distinct types, for example from untrusted schemas, should bound how many they create.
 func TypeOf(i any) Type {
-	return &RefractType{reflect.TypeOf(i)}
+	return toType(reflect.TypeOf(i))
+}
+
+// types interns the *RefractType for each reflect.Type seen so far. Entries are never removed;
+// see TypeOf.
+var types sync.Map // map[reflect.Type]*RefractType
+
+var nilType = &RefractType{}
+
+// toType returns the interned *RefractType for t.
+func toType(t reflect.Type) *RefractType {
+	if t == nil {
+		return nilType
+	}
+	if rt, ok := types.Load(t); ok {
+		return rt.(*RefractType)
+	}
+	rt, _ := types.LoadOrStore(t, &RefractType{t})
+	return rt.(*RefractType)
+}
+
+// TypesEqual reports whether a and b describe the same type. Unlike ==, it also holds for Types
+// that were not obtained from this package, such as a RefractType built by hand.
+func TypesEqual(a, b Type) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.ReflectType() == b.ReflectType()
 }
 
 func (t *RefractType) ReflectType() reflect.Type {
@@ -77,10 +117,10 @@ func (t *RefractType) String() string {
 
 func (t *RefractType) Implements(u Type) (bool, error) {
 	if u == nil {
-		return false, errors.New("reflect: nil type passed to Type.Implements")
+		return false, newError("reflect.Type.Implements", ErrNilType)
 	}
 	if u.Kind() != Interface {
-		return false, fmt.Errorf("reflect: non-interface type passed to Type.Implements")
+		return false, kindError("reflect.Type.Implements", "interface", reflect.Kind(u.Kind()))
 	}
 	return t.T.Implements(u.ReflectType()), nil
 }
@@ -103,26 +143,12 @@ func (t *RefractType) NumMethod() int {
 
 func (t *RefractType) Method(i int) (Method, error) {
 	if t.Kind() == Interface {
-		m := t.T.Method(i)
-		return Method{
-			Name:    m.Name,
-			PkgPath: m.PkgPath,
-			Type:    TypeOf(m.Type),
-			Func:    ValueOf(m.Func),
-			Index:   m.Index,
-		}, nil
+		return toMethod(t.T.Method(i)), nil
 	}
 	if i < 0 || i >= t.T.NumMethod() {
-		return Method{}, errors.New("reflect: Method index out of range")
+		return Method{}, rangeError("reflect.Type.Method", i, t.T.NumMethod())
 	}
-	m := t.T.Method(i)
-	return Method{
-		Name:    m.Name,
-		PkgPath: m.PkgPath,
-		Type:    TypeOf(m.Type),
-		Func:    ValueOf(m.Func),
-		Index:   m.Index,
-	}, nil
+	return toMethod(t.T.Method(i)), nil
 }
 
 func (t *RefractType) MethodByName(name string) (Method, bool) {
@@ -130,32 +156,26 @@ func (t *RefractType) MethodByName(name string) (Method, bool) {
 	if !ok {
 		return Method{}, false
 	}
-	return Method{
-		Name:    m.Name,
-		PkgPath: m.PkgPath,
-		Type:    TypeOf(m.Type),
-		Func:    ValueOf(m.Func),
-		Index:   m.Index,
-	}, true
+	return toMethod(m), true
 }
 
 func (t *RefractType) ChanDir() (ChanDir, error) {
 	if t.Kind() != Chan {
-		return 0, fmt.Errorf("reflect: ChanDir of non-chan type %s", t.String())
+		return 0, kindError("reflect.Type.ChanDir", "chan", t.T.Kind())
 	}
 	return ChanDir(t.T.ChanDir()), nil
 }
 
 func (t *RefractType) AssignableTo(u Type) (bool, error) {
 	if u == nil {
-		return false, errors.New("reflect: nil type passed to Type.AssignableTo")
+		return false, newError("reflect.Type.AssignableTo", ErrNilType)
 	}
 	return t.T.AssignableTo(u.ReflectType()), nil
 }
 
 func (t *RefractType) ConvertibleTo(u Type) (bool, error) {
 	if u == nil {
-		return false, errors.New("reflect: nil type passed to Type.ConvertibleTo")
+		return false, newError("reflect.Type.ConvertibleTo", ErrNilType)
 	}
 	return t.T.ConvertibleTo(u.ReflectType()), nil
 }
@@ -170,51 +190,33 @@ func (t *RefractType) Bits() int {
 
 func (t *RefractType) IsVariadic() (bool, error) {
 	if t.Kind() != Func {
-		return false, fmt.Errorf("reflect: IsVariadic of non-func type %s", t.String())
+		return false, kindError("reflect.Type.IsVariadic", "func", t.T.Kind())
 	}
 	return t.T.IsVariadic(), nil
 }
 
 func (t *RefractType) Elem() Type {
-	return &RefractType{t.T.Elem()}
+	return toType(t.T.Elem())
 }
 
 func (t *RefractType) Field(i int) (StructField, error) {
 	if t.Kind() != Struct {
-		return StructField{}, fmt.Errorf("reflect: Field of non-struct type %s", t.String())
+		return StructField{}, kindError("reflect.Type.Field", "struct", t.T.Kind())
 	}
 	if i < 0 || i >= t.T.NumField() {
-		return StructField{}, errors.New("reflect: Field index out of range")
+		return StructField{}, rangeError("reflect.Type.Field", i, t.T.NumField())
 	}
-	f := t.T.Field(i)
-	return StructField{
-		Name:      f.Name,
-		PkgPath:   f.PkgPath,
-		Type:      TypeOf(f.Type),
-		Tag:       StructTag(f.Tag),
-		Offset:    f.Offset,
-		Index:     f.Index,
-		Anonymous: f.Anonymous,
-	}, nil
+	return toStructField(t.T.Field(i)), nil
 }
 
 func (t *RefractType) FieldByIndex(index []int) (StructField, error) {
 	if t.Kind() != Struct {
-		return StructField{}, fmt.Errorf("reflect: FieldByIndex of non-struct type %s", t.String())
+		return StructField{}, kindError("reflect.Type.FieldByIndex", "struct", t.T.Kind())
 	}
 	if len(index) == 0 {
-		return StructField{}, errors.New("reflect: FieldByIndex index is empty")
+		return StructField{}, argError("reflect.Type.FieldByIndex", "non-empty index", "")
 	}
-	tt := t.T.FieldByIndex(index)
-	return StructField{
-		Name:      tt.Name,
-		PkgPath:   tt.PkgPath,
-		Type:      TypeOf(tt.Type),
-		Tag:       StructTag(tt.Tag),
-		Offset:    tt.Offset,
-		Index:     tt.Index,
-		Anonymous: tt.Anonymous,
-	}, nil
+	return toStructField(t.T.FieldByIndex(index)), nil
 }
 
 func (t *RefractType) FieldByName(name string) (StructField, bool) {
@@ -225,15 +227,7 @@ func (t *RefractType) FieldByName(name string) (StructField, bool) {
 	if !ok {
 		return StructField{}, false
 	}
-	return StructField{
-		Name:      f.Name,
-		PkgPath:   f.PkgPath,
-		Type:      TypeOf(f.Type),
-		Tag:       StructTag(f.Tag),
-		Offset:    f.Offset,
-		Index:     f.Index,
-		Anonymous: f.Anonymous,
-	}, true
+	return toStructField(f), true
 }
 
 func (t *RefractType) FieldByNameFunc(match func(string) bool) (StructField, bool) {
@@ -244,92 +238,254 @@ func (t *RefractType) FieldByNameFunc(match func(string) bool) (StructField, boo
 	if !ok {
 		return StructField{}, false
 	}
-	return StructField{
-		Name:      f.Name,
-		PkgPath:   f.PkgPath,
-		Type:      TypeOf(f.Type),
-		Tag:       StructTag(f.Tag),
-		Offset:    f.Offset,
-		Index:     f.Index,
-		Anonymous: f.Anonymous,
-	}, true
+	return toStructField(f), true
+}
+
+// VisibleFields returns all the visible fields of the struct type t, including the fields
+// promoted from embedded structs, in the order documented for reflect.VisibleFields.
+func (t *RefractType) VisibleFields() ([]StructField, error) {
+	if t.T == nil {
+		return nil, newError("reflect.VisibleFields", ErrNilType)
+	}
+	if t.Kind() != Struct {
+		return nil, kindError("reflect.VisibleFields", "struct", t.T.Kind())
+	}
+	vf := reflect.VisibleFields(t.T)
+	out := make([]StructField, len(vf))
+	for i, f := range vf {
+		out[i] = toStructField(f)
+	}
+	return out, nil
+}
+
+// LookupField returns the field with the given name, either declared directly in the struct
+// type t or promoted from an embedded struct, with its full index path. Unlike FieldByName,
+// which returns no field when the name is ambiguous, LookupField returns an error naming the
+// conflicting index paths.
+func (t *RefractType) LookupField(name string) (StructField, error) {
+	if t.T == nil {
+		return StructField{}, newError("safereflect.Type.LookupField", ErrNilType)
+	}
+	if t.Kind() != Struct {
+		return StructField{}, kindError("safereflect.Type.LookupField", "struct", t.T.Kind())
+	}
+	type scan struct {
+		typ   reflect.Type
+		index []int
+	}
+	current := []scan{{typ: t.T}}
+	visited := make(map[reflect.Type]bool)
+	for len(current) > 0 {
+		var next []scan
+		var matches []reflect.StructField
+		for _, s := range current {
+			if visited[s.typ] {
+				continue
+			}
+			for i := 0; i < s.typ.NumField(); i++ {
+				f := s.typ.Field(i)
+				index := append(append([]int(nil), s.index...), i)
+				if f.Name == name {
+					f.Index = index
+					matches = append(matches, f)
+					continue
+				}
+				if f.Anonymous {
+					ft := f.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, scan{typ: ft, index: index})
+					}
+				}
+			}
+		}
+		// Types are marked once the whole depth has been scanned, so the same type embedded
+		// twice at one depth is still seen twice and reported as ambiguous.
+		for _, s := range current {
+			visited[s.typ] = true
+		}
+		switch len(matches) {
+		case 0:
+			current = next
+		case 1:
+			return toStructField(matches[0]), nil
+		default:
+			paths := make([]string, len(matches))
+			for i, m := range matches {
+				paths[i] = fmt.Sprint(m.Index)
+			}
+			e := newError("safereflect.Type.LookupField", ErrAmbiguous)
+			e.Expected = "one field named " + name
+			e.Actual = "fields at index paths " + strings.Join(paths, ", ")
+			return StructField{}, e
+		}
+	}
+	e := newError("safereflect.Type.LookupField", ErrNotFound)
+	e.Expected = "field named " + name
+	e.Actual = t.String()
+	return StructField{}, e
 }
 
 func (t *RefractType) Key() (Type, error) {
 	if t.Kind() != Map {
-		return nil, fmt.Errorf("reflect: Key of non-map type %s", t.String())
+		return nil, kindError("reflect.Type.Key", "map", t.T.Kind())
 	}
-	return TypeOf(t.T.Key()), nil
+	return toType(t.T.Key()), nil
 }
 
 func (t *RefractType) Len() (int, error) {
 	if t.Kind() != Array {
-		return 0, fmt.Errorf("reflect: Len of non-array type %s", t.String())
+		return 0, kindError("reflect.Type.Len", "array", t.T.Kind())
 	}
 	return t.T.Len(), nil
 }
 
 func (t *RefractType) NumField() (int, error) {
 	if t.Kind() != Struct {
-		return 0, fmt.Errorf("reflect: NumField of non-struct type %s", t.String())
+		return 0, kindError("reflect.Type.NumField", "struct", t.T.Kind())
 	}
 	return t.T.NumField(), nil
 }
 
 func (t *RefractType) NumIn() (int, error) {
 	if t.Kind() != Func {
-		return 0, fmt.Errorf("reflect: NumIn of non-func type %s", t.String())
+		return 0, kindError("reflect.Type.NumIn", "func", t.T.Kind())
 	}
 	return t.T.NumIn(), nil
 }
 
 func (t *RefractType) NumOut() (int, error) {
 	if t.Kind() != Func {
-		return 0, fmt.Errorf("reflect: NumOut of non-func type %s", t.String())
+		return 0, kindError("reflect.Type.NumOut", "func", t.T.Kind())
 	}
 	return t.T.NumOut(), nil
 }
 
 func (t *RefractType) In(i int) (Type, error) {
 	if t.Kind() != Func {
-		return nil, fmt.Errorf("reflect: In of non-func type %s", t.String())
+		return nil, kindError("reflect.Type.In", "func", t.T.Kind())
 	}
-	return TypeOf(t.T.In(i)), nil
+	return toType(t.T.In(i)), nil
 }
 
 func (t *RefractType) Out(i int) (Type, error) {
 	if t.Kind() != Func {
-		return nil, fmt.Errorf("reflect: Out of non-func type %s", t.String())
+		return nil, kindError("reflect.Type.Out", "func", t.T.Kind())
 	}
-	return TypeOf(t.T.Out(i)), nil
+	return toType(t.T.Out(i)), nil
 }
 
 func TypeFor[T any]() Type {
-	return &RefractType{reflect.TypeFor[T]()}
+	return toType(reflect.TypeFor[T]())
 }
 
 func MapOf(key, elem Type) (Type, error) {
-	if !ValueOf(key).Comparable() {
-		return nil, errors.New("reflect.MapOf with incomparable key type")
+	if key == nil || key.ReflectType() == nil || elem == nil || elem.ReflectType() == nil {
+		return nil, newError("reflect.MapOf", ErrNilType)
+	}
+	if !key.Comparable() {
+		e := newError("reflect.MapOf", ErrWrongType)
+		e.Expected = "comparable key type"
+		e.Actual = typeString(key.ReflectType())
+		return nil, e
 	}
-	return &RefractType{reflect.MapOf(key.ReflectType(), elem.ReflectType())}, nil
+	return toType(reflect.MapOf(key.ReflectType(), elem.ReflectType())), nil
 }
 
 func SliceOf(t Type) Type {
-	return &RefractType{reflect.SliceOf(t.ReflectType())}
+	return toType(reflect.SliceOf(t.ReflectType()))
 }
 
-// StructOf returns a new struct type with the given fields. StructOf can panic due to the complexity of requirements for structs (unexported fields, etc).
-func StructOf(fields []StructField) (Type, error) {
-	var f []reflect.StructField
-	for _, field := range fields {
-		if field.Name == "" {
-			return nil, errors.New("reflect.StructOf: field " + field.Type.String() + " has no name")
+// ArrayOf returns the array type with the given length and element type.
+func ArrayOf(length int, elem Type) (Type, error) {
+	if elem == nil || elem.ReflectType() == nil {
+		return nil, newError("reflect.ArrayOf", ErrNilType)
+	}
+	if length < 0 {
+		return nil, argError("reflect.ArrayOf", "non-negative length", strconv.Itoa(length))
+	}
+	if size := elem.Size(); size > 0 && uintptr(length) > ^uintptr(0)/size {
+		return nil, argError("reflect.ArrayOf", "array size within the virtual address space", strconv.Itoa(length)+" elements of "+strconv.FormatUint(uint64(size), 10)+" bytes")
+	}
+	return toType(reflect.ArrayOf(length, elem.ReflectType())), nil
+}
+
+// ChanOf returns the channel type with the given direction and element type.
+func ChanOf(dir ChanDir, elem Type) (Type, error) {
+	if elem == nil || elem.ReflectType() == nil {
+		return nil, newError("reflect.ChanOf", ErrNilType)
+	}
+	if dir != RecvDir && dir != SendDir && dir != BothDir {
+		return nil, argError("reflect.ChanOf", "RecvDir, SendDir or BothDir", strconv.Itoa(int(dir)))
+	}
+	if elem.Size() >= 1<<16 {
+		return nil, argError("reflect.ChanOf", "element size below 65536 bytes", strconv.FormatUint(uint64(elem.Size()), 10))
+	}
+	return toType(reflect.ChanOf(reflect.ChanDir(dir), elem.ReflectType())), nil
+}
+
+// FuncOf returns the function type with the given argument and result types. If variadic is
+// true, the last argument type must be a slice and represents the variadic parameter.
+func FuncOf(in, out []Type, variadic bool) (Type, error) {
+	if len(in)+len(out) > 128 {
+		return nil, argError("reflect.FuncOf", "at most 128 arguments and results", strconv.Itoa(len(in)+len(out)))
+	}
+	rin, err := reflectTypes("reflect.FuncOf", "argument type", in)
+	if err != nil {
+		return nil, err
+	}
+	rout, err := reflectTypes("reflect.FuncOf", "result type", out)
+	if err != nil {
+		return nil, err
+	}
+	if variadic && (len(rin) == 0 || rin[len(rin)-1].Kind() != reflect.Slice) {
+		e := newError("reflect.FuncOf", ErrWrongKind)
+		e.Expected = "slice as last argument of variadic func"
+		if len(rin) > 0 {
+			e.Index = len(rin) - 1
+			e.Actual = rin[len(rin)-1].Kind().String()
 		}
-		if field.Type == nil {
-			return nil, errors.New("reflect.StructOf: field " + field.Name + " has no type")
+		return nil, e
+	}
+	return toType(reflect.FuncOf(rin, rout, variadic)), nil
+}
+
+// PointerTo returns the pointer type with element t.
+func PointerTo(t Type) (Type, error) {
+	if t == nil || t.ReflectType() == nil {
+		return nil, newError("reflect.PointerTo", ErrNilType)
+	}
+	return toType(reflect.PointerTo(t.ReflectType())), nil
+}
+
+func reflectTypes(method, what string, types []Type) ([]reflect.Type, error) {
+	out := make([]reflect.Type, len(types))
+	for i, t := range types {
+		if t == nil || t.ReflectType() == nil {
+			e := newError(method, ErrNilType)
+			e.Index = i
+			e.Expected = what
+			return nil, e
 		}
-		f = append(f, reflect.StructField{
+		out[i] = t.ReflectType()
+	}
+	return out, nil
+}
+
+// StructOf returns a new struct type with the given fields. The fields are validated against
+// every rule reflect.StructOf enforces by panicking; all violations are returned together as
+// *StructFieldError values joined with errors.Join. Any remaining panic is recovered and
+// returned as a *PanicError. Like reflect.StructOf, StructOf does not check the syntax of tags;
+// use ValidateStructTags for that.
+func StructOf(fields []StructField) (Type, error) {
+	if err := validateStructFields(fields); err != nil {
+		return nil, err
+	}
+	f := make([]reflect.StructField, len(fields))
+	for i, field := range fields {
+		f[i] = reflect.StructField{
 			Name:      field.Name,
 			PkgPath:   field.PkgPath,
 			Type:      field.Type.ReflectType(),
@@ -337,7 +493,14 @@ func StructOf(fields []StructField) (Type, error) {
 			Offset:    field.Offset,
 			Index:     field.Index,
 			Anonymous: field.Anonymous,
-		})
+		}
+	}
+	var out reflect.Type
+	if err := Try(func() error {
+		out = reflect.StructOf(f)
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	return &RefractType{reflect.StructOf(f)}, nil
+	return toType(out), nil
 }
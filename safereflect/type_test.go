@@ -0,0 +1,223 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestTypeConstructors(t *testing.T) {
+	intType, stringType := TypeOf(0), TypeOf("")
+	big := TypeOf([1 << 16]byte{})
+
+	tests := []struct {
+		name    string
+		build   func() (Type, error)
+		want    reflect.Type
+		wantErr error
+	}{
+		{name: "ArrayOf", build: func() (Type, error) { return ArrayOf(3, intType) }, want: reflect.TypeFor[[3]int]()},
+		{name: "ArrayOf empty", build: func() (Type, error) { return ArrayOf(0, intType) }, want: reflect.TypeFor[[0]int]()},
+		{name: "ArrayOf negative length", build: func() (Type, error) { return ArrayOf(-1, intType) }, wantErr: ErrInvalidArgument},
+		{name: "ArrayOf too large", build: func() (Type, error) { return ArrayOf(1<<62, TypeOf(int64(0))) }, wantErr: ErrInvalidArgument},
+		{name: "ArrayOf nil element", build: func() (Type, error) { return ArrayOf(1, nil) }, wantErr: ErrNilType},
+		{name: "ChanOf both", build: func() (Type, error) { return ChanOf(BothDir, intType) }, want: reflect.TypeFor[chan int]()},
+		{name: "ChanOf recv", build: func() (Type, error) { return ChanOf(RecvDir, intType) }, want: reflect.TypeFor[<-chan int]()},
+		{name: "ChanOf send", build: func() (Type, error) { return ChanOf(SendDir, intType) }, want: reflect.TypeFor[chan<- int]()},
+		{name: "ChanOf invalid direction", build: func() (Type, error) { return ChanOf(0, intType) }, wantErr: ErrInvalidArgument},
+		{name: "ChanOf large element", build: func() (Type, error) { return ChanOf(BothDir, big) }, wantErr: ErrInvalidArgument},
+		{name: "ChanOf nil element", build: func() (Type, error) { return ChanOf(BothDir, nil) }, wantErr: ErrNilType},
+		{
+			name: "FuncOf",
+			build: func() (Type, error) {
+				return FuncOf([]Type{intType}, []Type{stringType, TypeOf((*error)(nil)).Elem()}, false)
+			},
+			want: reflect.TypeFor[func(int) (string, error)](),
+		},
+		{
+			name:  "FuncOf variadic",
+			build: func() (Type, error) { return FuncOf([]Type{intType, SliceOf(stringType)}, nil, true) },
+			want:  reflect.TypeFor[func(int, ...string)](),
+		},
+		{name: "FuncOf variadic without slice", build: func() (Type, error) { return FuncOf([]Type{intType}, nil, true) }, wantErr: ErrWrongKind},
+		{name: "FuncOf variadic without arguments", build: func() (Type, error) { return FuncOf(nil, nil, true) }, wantErr: ErrWrongKind},
+		{name: "FuncOf nil argument", build: func() (Type, error) { return FuncOf([]Type{nil}, nil, false) }, wantErr: ErrNilType},
+		{name: "FuncOf too many arguments", build: func() (Type, error) { return FuncOf(make([]Type, 129), nil, false) }, wantErr: ErrInvalidArgument},
+		{name: "PointerTo", build: func() (Type, error) { return PointerTo(intType) }, want: reflect.TypeFor[*int]()},
+		{name: "PointerTo nil", build: func() (Type, error) { return PointerTo(nil) }, wantErr: ErrNilType},
+		{name: "MapOf", build: func() (Type, error) { return MapOf(stringType, intType) }, want: reflect.TypeFor[map[string]int]()},
+		{name: "MapOf incomparable key", build: func() (Type, error) { return MapOf(SliceOf(intType), intType) }, wantErr: ErrWrongType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.build()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got.ReflectType() != tt.want {
+				t.Errorf("type = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type fieldInner struct {
+	ID   int
+	Name string
+}
+
+type fieldOther struct {
+	Name string
+}
+
+type fieldOuter struct {
+	fieldInner
+	*fieldOther
+	Own  bool
+	Deep struct{ ID int }
+}
+
+func TestLookupField(t *testing.T) {
+	type shadow struct {
+		fieldInner
+		ID string
+	}
+
+	tests := []struct {
+		name      string
+		typ       Type
+		field     string
+		wantIndex []int
+		wantErr   error
+	}{
+		{name: "declared", typ: TypeOf(fieldOuter{}), field: "Own", wantIndex: []int{2}},
+		{name: "promoted", typ: TypeOf(fieldOuter{}), field: "ID", wantIndex: []int{0, 0}},
+		{name: "embedded field itself", typ: TypeOf(fieldOuter{}), field: "fieldOther", wantIndex: []int{1}},
+		{name: "shallower field wins", typ: TypeOf(shadow{}), field: "ID", wantIndex: []int{1}},
+		{name: "ambiguous", typ: TypeOf(fieldOuter{}), field: "Name", wantErr: ErrAmbiguous},
+		{name: "not found", typ: TypeOf(fieldOuter{}), field: "Missing", wantErr: ErrNotFound},
+		{name: "named struct field is not embedded", typ: TypeOf(struct{ Deep fieldInner }{}), field: "ID", wantErr: ErrNotFound},
+		{name: "not a struct", typ: TypeOf(0), field: "ID", wantErr: ErrWrongKind},
+		{name: "nil type", typ: TypeOf(nil), field: "ID", wantErr: ErrNilType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := tt.typ.LookupField(tt.field)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("LookupField() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LookupField() error = %v", err)
+			}
+			if !reflect.DeepEqual(f.Index, tt.wantIndex) {
+				t.Errorf("LookupField() index = %v, want %v", f.Index, tt.wantIndex)
+			}
+		})
+	}
+}
+
+func TestVisibleFields(t *testing.T) {
+	fields, err := TypeOf(fieldOuter{}).VisibleFields()
+	if err != nil {
+		t.Fatalf("VisibleFields() error = %v", err)
+	}
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	// The ambiguous Name fields are hidden, as with reflect.VisibleFields.
+	want := []string{"fieldInner", "ID", "fieldOther", "Own", "Deep"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("VisibleFields() = %v, want %v", names, want)
+	}
+	for _, typ := range []Type{TypeOf(nil), TypeOf("")} {
+		if _, err := typ.VisibleFields(); err == nil {
+			t.Errorf("%v.VisibleFields() error = nil, want an error", typ)
+		}
+	}
+}
+
+func TestTypeInterning(t *testing.T) {
+	dynamic := func() Type {
+		typ, err := StructOf([]StructField{{Name: "A", Type: TypeOf(0), Tag: `json:"a"`}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return typ
+	}
+	field := func() Type {
+		f, err := TypeOf(struct{ S []int }{}).Field(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f.Type
+	}
+
+	tests := []struct {
+		name string
+		a, b Type
+	}{
+		{name: "TypeOf", a: TypeOf(1), b: TypeOf(2)},
+		{name: "TypeFor", a: TypeFor[string](), b: TypeOf("")},
+		{name: "Value.Type", a: ValueOf(1.5).Type(), b: TypeOf(0.0)},
+		{name: "Elem", a: TypeOf(new(int)).Elem(), b: TypeOf(0)},
+		{name: "SliceOf", a: SliceOf(TypeOf(0)), b: TypeOf([]int{})},
+		{name: "Field type", a: field(), b: TypeOf([]int{})},
+		{name: "StructOf", a: dynamic(), b: dynamic()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.a != tt.b {
+				t.Errorf("%v and %v are different Types, want the same interned Type", tt.a, tt.b)
+			}
+			if !TypesEqual(tt.a, tt.b) {
+				t.Errorf("TypesEqual(%v, %v) = false", tt.a, tt.b)
+			}
+		})
+	}
+}
+
+func TestTypeInterningConcurrent(t *testing.T) {
+	// A type that no other test has interned yet, so every goroutine races to store it.
+	type fresh struct{ N int }
+	got := make(chan Type, 8)
+	for range cap(got) {
+		go func() { got <- TypeOf(fresh{}) }()
+	}
+	first := <-got
+	for range cap(got) - 1 {
+		if typ := <-got; typ != first {
+			t.Fatalf("concurrent TypeOf returned different Types %p and %p", typ, first)
+		}
+	}
+}
+
+func TestTypesEqual(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Type
+		want bool
+	}{
+		{name: "interned", a: TypeOf(0), b: TypeOf(0), want: true},
+		{name: "built by hand", a: &RefractType{T: reflect.TypeFor[int]()}, b: TypeOf(0), want: true},
+		{name: "different", a: TypeOf(0), b: TypeOf(int64(0))},
+		{name: "nil and type", a: nil, b: TypeOf(0)},
+		{name: "both nil", a: nil, b: nil, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TypesEqual(tt.a, tt.b); got != tt.want {
+				t.Errorf("TypesEqual() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
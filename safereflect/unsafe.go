@@ -0,0 +1,46 @@
+package safereflect
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// unsafeDisabled is set by DisableUnsafeExported. Building with the safereflect_nounsafe tag
+// disables UnsafeExported regardless of it.
+var unsafeDisabled atomic.Bool
+
+// DisableUnsafeExported turns UnsafeExported off for the rest of the life of the program. There
+// is deliberately no way to turn it back on, so a production binary can call it early in main to
+// make sure no code path reads or writes unexported fields. To remove the capability at compile
+// time instead, build with the safereflect_nounsafe tag.
+func DisableUnsafeExported() {
+	unsafeDisabled.Store(true)
+}
+
+// UnsafeExportedEnabled reports whether UnsafeExported is available.
+func UnsafeExportedEnabled() bool {
+	return unsafeExportedBuild && !unsafeDisabled.Load()
+}
+
+// UnsafeExported returns a view of v that is not marked as obtained through an unexported struct
+// field, so it can be read with Interface and, being addressable, written with Set. This
+// bypasses the visibility rules of Go and is meant for test harnesses and debuggers. v must be
+// addressable, which is the case for the fields of a struct reached through a pointer. It is
+// an error wrapping ErrUnsafeDisabled when the capability was turned off by
+// DisableUnsafeExported or the safereflect_nounsafe build tag.
+func (v Value) UnsafeExported() (Value, error) {
+	if !UnsafeExportedEnabled() {
+		return Value{}, newError("safereflect.Value.UnsafeExported", ErrUnsafeDisabled)
+	}
+	if !v.V.IsValid() {
+		return Value{}, newError("safereflect.Value.UnsafeExported", ErrInvalidValue)
+	}
+	if !v.CanAddr() {
+		return Value{}, newError("safereflect.Value.UnsafeExported", ErrUnaddressable)
+	}
+	if v.V.CanSet() {
+		return v, nil
+	}
+	return Value{reflect.NewAt(v.V.Type(), unsafe.Pointer(v.V.UnsafeAddr())).Elem()}, nil
+}
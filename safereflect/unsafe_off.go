@@ -0,0 +1,5 @@
+//go:build safereflect_nounsafe
+
+package safereflect
+
+const unsafeExportedBuild = false
@@ -0,0 +1,102 @@
+package safereflect
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+type unsafeHolder struct {
+	Public  int
+	private string
+	nested  struct{ n []int }
+}
+
+func TestUnsafeExported(t *testing.T) {
+	if !UnsafeExportedEnabled() {
+		t.Skip("UnsafeExported is disabled in this build")
+	}
+	h := &unsafeHolder{Public: 1, private: "p"}
+	h.nested.n = []int{1}
+	elem, _ := ValueOf(h).Elem()
+	byValue := ValueOf(*h)
+
+	field := func(v Value, i int) Value { return Value{v.V.Field(i)} }
+
+	tests := []struct {
+		name    string
+		v       Value
+		want    any
+		wantErr error
+	}{
+		{name: "unexported field", v: field(elem, 1), want: "p"},
+		{name: "exported field", v: field(elem, 0), want: 1},
+		{name: "field of unexported field", v: Value{field(elem, 2).V.Field(0)}, want: []int{1}},
+		{name: "unaddressable field", v: field(byValue, 1), wantErr: ErrUnaddressable},
+		{name: "zero Value", v: Value{}, wantErr: ErrInvalidValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.v.UnsafeExported()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("UnsafeExported() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UnsafeExported() error = %v", err)
+			}
+			x, err := got.Interface()
+			if err != nil {
+				t.Fatalf("Interface() error = %v", err)
+			}
+			if !DeepEqual(x, tt.want) {
+				t.Errorf("Interface() = %v, want %v", x, tt.want)
+			}
+		})
+	}
+
+	t.Run("set", func(t *testing.T) {
+		private, err := field(elem, 1).UnsafeExported()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := private.Set(ValueOf("changed")); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if h.private != "changed" {
+			t.Errorf("private = %q, want changed", h.private)
+		}
+	})
+}
+
+// TestDisableUnsafeExported runs in a child process, as DisableUnsafeExported can not be undone
+// and would affect every test that runs after it.
+func TestDisableUnsafeExported(t *testing.T) {
+	if os.Getenv("SAFEREFLECT_TEST_DISABLE_UNSAFE") != "1" {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestDisableUnsafeExported$")
+		cmd.Env = append(os.Environ(), "SAFEREFLECT_TEST_DISABLE_UNSAFE=1")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("child process failed: %v\n%s", err, out)
+		}
+		return
+	}
+
+	h := &unsafeHolder{private: "p"}
+	elem, _ := ValueOf(h).Elem()
+	public, private := Value{elem.V.Field(0)}, Value{elem.V.Field(1)}
+	DisableUnsafeExported()
+	DisableUnsafeExported()
+	if UnsafeExportedEnabled() {
+		t.Fatal("UnsafeExportedEnabled() = true after DisableUnsafeExported")
+	}
+	if _, err := private.UnsafeExported(); !errors.Is(err, ErrUnsafeDisabled) {
+		t.Fatalf("UnsafeExported() error = %v, want %v", err, ErrUnsafeDisabled)
+	}
+	// Exported fields are refused as well, so callers fail the same way whatever they pass.
+	if _, err := public.UnsafeExported(); !errors.Is(err, ErrUnsafeDisabled) {
+		t.Fatalf("UnsafeExported() error = %v, want %v", err, ErrUnsafeDisabled)
+	}
+}
@@ -1,8 +1,9 @@
 package safereflect
 
 import (
-	"errors"
+	"fmt"
 	"reflect"
+	"strconv"
 	"unsafe"
 )
 
@@ -23,21 +24,21 @@ func (v Value) Addr() (Value, error) {
 	if v.V.CanAddr() {
 		return Value{v.V.Addr()}, nil
 	}
-	return Value{}, errors.New("reflect.Value.Addr of unaddressable value")
+	return Value{}, newError("reflect.Value.Addr", ErrUnaddressable)
 }
 
 func (v Value) Bool() (bool, error) {
 	if v.V.Kind() == reflect.Bool {
 		return v.V.Bool(), nil
 	}
-	return false, errors.New("reflect.Value.Bool of non-bool value")
+	return false, kindError("reflect.Value.Bool", "bool", v.V.Kind())
 }
 
 func (v Value) Bytes() ([]byte, error) {
 	if v.V.Kind() == reflect.Slice && v.V.Type().Elem().Kind() == reflect.Uint8 {
 		return v.V.Bytes(), nil
 	}
-	return nil, errors.New("reflect.Value.Bytes of non-[]byte value")
+	return nil, kindError("reflect.Value.Bytes", "[]byte", v.V.Kind())
 }
 
 func (v Value) CanAddr() bool {
@@ -48,46 +49,123 @@ func (v Value) CanSet() bool {
 	return v.V.CanSet()
 }
 
+// Call calls the function v with the input arguments args. Unlike reflect, the arguments are
+// validated against the function signature before the call is made: wrong arity, zero Values,
+// unexported values and arguments that are not assignable to their parameter type are reported
+// as errors naming the offending parameter index. A panic raised by the function itself is
+// recovered and returned as a *PanicError.
 func (v Value) Call(args []Value) ([]Value, error) {
+	if err := v.validateCall("reflect.Value.Call", args, false); err != nil {
+		return nil, err
+	}
+	return v.call(args, false)
+}
+
+// CallSlice calls the variadic function v with the input arguments args, assigning the slice
+// args[len(args)-1] to v's final variadic argument. The same validation as Call is applied.
+func (v Value) CallSlice(args []Value) ([]Value, error) {
+	if err := v.validateCall("reflect.Value.CallSlice", args, true); err != nil {
+		return nil, err
+	}
+	return v.call(args, true)
+}
+
+func (v Value) validateCall(op string, args []Value, isSlice bool) error {
 	if v.V.Kind() != reflect.Func {
-		return nil, errors.New("reflect.Value.Call of non-function")
+		return kindError(op, "func", v.V.Kind())
 	}
-	var in []reflect.Value
-	for _, arg := range args {
-		in = append(in, arg.V)
+	if !v.V.CanInterface() {
+		return newError(op, ErrUnexported)
 	}
-	out := v.V.Call(in)
-	var outv []Value
-	for _, v := range out {
-		outv = append(outv, Value{v})
+	if v.V.IsNil() {
+		return newError(op, ErrNilValue)
 	}
-	return outv, nil
+	t := v.V.Type()
+	n := t.NumIn()
+	variadic := t.IsVariadic()
+	if isSlice {
+		if !variadic {
+			return kindError(op, "variadic func", v.V.Kind())
+		}
+		if len(args) != n {
+			return argError(op, fmt.Sprintf("%d arguments", n), strconv.Itoa(len(args)))
+		}
+	} else if variadic {
+		if len(args) < n-1 {
+			return argError(op, fmt.Sprintf("at least %d arguments", n-1), strconv.Itoa(len(args)))
+		}
+	} else if len(args) != n {
+		return argError(op, fmt.Sprintf("%d arguments", n), strconv.Itoa(len(args)))
+	}
+	for i, arg := range args {
+		expected := t.In(min(i, n-1))
+		if variadic && !isSlice && i >= n-1 {
+			expected = expected.Elem()
+		}
+		var e *ValueError
+		switch {
+		case !arg.V.IsValid():
+			e = newError(op, ErrInvalidValue)
+			e.Expected = expected.String()
+		case !arg.V.CanInterface():
+			e = newError(op, ErrUnexported)
+			e.Actual = arg.V.Type().String()
+		case !arg.V.Type().AssignableTo(expected):
+			e = newError(op, ErrWrongType)
+			e.Expected = expected.String()
+			e.Actual = arg.V.Type().String()
+		default:
+			continue
+		}
+		e.Index = i
+		return e
+	}
+	return nil
 }
 
-func (v Value) CallSlice(args []Value) ([]Value, error) {
-	if v.V.Kind() != reflect.Func {
-		return nil, errors.New("reflect.Value.Call of non-function")
+func (v Value) call(args []Value, isSlice bool) ([]Value, error) {
+	in := make([]reflect.Value, len(args))
+	for i, arg := range args {
+		in[i] = arg.V
 	}
-	var in []reflect.Value
-	for _, arg := range args {
-		in = append(in, arg.V)
+	var out []reflect.Value
+	if err := Try(func() error {
+		if isSlice {
+			out = v.V.CallSlice(in)
+		} else {
+			out = v.V.Call(in)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
 	}
-	out := v.V.Call(in)
-	var outv []Value
-	for _, v := range out {
-		outv = append(outv, Value{v})
+	outv := make([]Value, len(out))
+	for i, o := range out {
+		outv[i] = Value{o}
 	}
 	return outv, nil
 }
 
-func (v Value) Close() {
+// Close closes the channel v. Closing a non-chan value, a receive-only channel, a nil channel
+// or a channel that is already closed is reported as an error.
+func (v Value) Close() error {
 	if v.V.Kind() != reflect.Chan {
-		return
+		return kindError("reflect.Value.Close", "chan", v.V.Kind())
+	}
+	if v.V.Type().ChanDir()&reflect.SendDir == 0 {
+		return typeError("reflect.Value.Close", reflect.ChanOf(reflect.SendDir, v.V.Type().Elem()), v.V.Type())
 	}
-	if v.V.Type().ChanDir()&reflect.ChanDir(SendDir) == 0 {
-		return
+	if v.V.IsNil() {
+		return newError("reflect.Value.Close", ErrNilValue)
 	}
-	v.V.Close()
+	err := Try(func() error {
+		v.V.Close()
+		return nil
+	})
+	if isRuntimePanic(err, "close of closed channel") {
+		return newError("reflect.Value.Close", ErrClosed)
+	}
+	return err
 }
 
 func (v Value) CanComplex() bool {
@@ -98,59 +176,71 @@ func (v Value) Complex() (complex128, error) {
 	if v.V.Kind() == reflect.Complex64 || v.V.Kind() == reflect.Complex128 {
 		return v.V.Complex(), nil
 	}
-	return 0, errors.New("reflect.Value.Complex of non-complex value")
+	return 0, kindError("reflect.Value.Complex", "complex", v.V.Kind())
 }
 
-// Can still panic if this is a pointer to a not-in-heap object
+// Elem returns the value that the interface v contains or that the pointer v points to.
+// A panic from a pointer to a not-in-heap object is recovered and returned as a *PanicError.
 func (v Value) Elem() (Value, error) {
-	if v.V.Kind() == reflect.Ptr || v.V.Kind() == reflect.Interface {
-		return Value{v.V.Elem()}, nil
+	if v.V.Kind() != reflect.Ptr && v.V.Kind() != reflect.Interface {
+		return Value{}, kindError("reflect.Value.Elem", "ptr or interface", v.V.Kind())
 	}
-	return Value{}, errors.New("reflect.Value.Elem of non-pointer")
-
+	var out reflect.Value
+	err := Try(func() error {
+		out = v.V.Elem()
+		return nil
+	})
+	return Value{out}, err
 }
 
 func (v Value) Field(i int) (Value, error) {
 	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.Field of non-struct value")
+		return Value{}, kindError("reflect.Value.Field", "struct", v.V.Kind())
 	}
 	if i < 0 || i >= v.V.NumField() {
-		return Value{}, errors.New("reflect.Value.Field index out of range")
+		return Value{}, rangeError("reflect.Value.Field", i, v.V.NumField())
 	}
 	return Value{v.V.Field(i)}, nil
 }
 
 func (v Value) FieldByIndex(index []int) (Value, error) {
 	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.FieldByIndex of non-struct value")
+		return Value{}, kindError("reflect.Value.FieldByIndex", "struct", v.V.Kind())
 	}
 	if len(index) == 0 {
-		return Value{}, errors.New("reflect.Value.FieldByIndex with no index")
+		return Value{}, argError("reflect.Value.FieldByIndex", "non-empty index", "")
 	}
 	return Value{v.V.FieldByIndex(index)}, nil
 }
 
-// Can still panic if indirection through a nil pointer to embedded struct field occurs
+// FieldByIndexErr is like FieldByIndex, but returns an error instead of panicking when the
+// index path goes through a nil pointer to an embedded struct.
 func (v Value) FieldByIndexErr(index []int) (Value, error) {
 	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.FieldByIndex of non-struct value")
+		return Value{}, kindError("reflect.Value.FieldByIndexErr", "struct", v.V.Kind())
 	}
 	if len(index) == 0 {
-		return Value{}, errors.New("reflect.Value.FieldByIndex with no index")
+		return Value{}, argError("reflect.Value.FieldByIndexErr", "non-empty index", "")
 	}
-	return Value{v.V.FieldByIndex(index)}, nil
+	out, err := v.V.FieldByIndexErr(index)
+	if err != nil {
+		e := newError("reflect.Value.FieldByIndexErr", ErrNilValue)
+		e.Actual = err.Error()
+		return Value{}, e
+	}
+	return Value{out}, nil
 }
 
 func (v Value) FieldByName(name string) (Value, error) {
 	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.FieldByName of non-struct value")
+		return Value{}, kindError("reflect.Value.FieldByName", "struct", v.V.Kind())
 	}
 	return Value{v.V.FieldByName(name)}, nil
 }
 
 func (v Value) FieldByNameFunc(match func(string) bool) (Value, error) {
 	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.FieldByNameFunc of non-struct value")
+		return Value{}, kindError("reflect.Value.FieldByNameFunc", "struct", v.V.Kind())
 	}
 	return Value{v.V.FieldByNameFunc(match)}, nil
 }
@@ -163,15 +253,15 @@ func (v Value) Float() (float64, error) {
 	if v.V.Kind() == reflect.Float32 || v.V.Kind() == reflect.Float64 {
 		return v.V.Float(), nil
 	}
-	return 0, errors.New("reflect.Value.Float of non-float value")
+	return 0, kindError("reflect.Value.Float", "float", v.V.Kind())
 }
 
 func (v Value) Index(i int) (Value, error) {
 	if v.V.Kind() != reflect.Array && v.V.Kind() != reflect.Slice && v.V.Kind() != reflect.String {
-		return Value{}, errors.New("reflect.Value.Index of non-array, non-slice, non-string value")
+		return Value{}, kindError("reflect.Value.Index", "array, slice or string", v.V.Kind())
 	}
 	if i < 0 || i >= v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Index index out of range")
+		return Value{}, rangeError("reflect.Value.Index", i, v.V.Len())
 	}
 	return Value{v.V.Index(i)}, nil
 }
@@ -184,7 +274,7 @@ func (v Value) Int() (int64, error) {
 	if v.V.Kind() == reflect.Int || v.V.Kind() == reflect.Int8 || v.V.Kind() == reflect.Int16 || v.V.Kind() == reflect.Int32 || v.V.Kind() == reflect.Int64 {
 		return v.V.Int(), nil
 	}
-	return 0, errors.New("reflect.Value.Int of non-int value")
+	return 0, kindError("reflect.Value.Int", "int", v.V.Kind())
 }
 
 func (v Value) CanInterface() bool {
@@ -193,14 +283,14 @@ func (v Value) CanInterface() bool {
 
 func (v Value) Interface() (any, error) {
 	if !v.V.CanInterface() {
-		return nil, errors.New("reflect.Value.Interface of unexported value")
+		return nil, newError("reflect.Value.Interface", ErrUnexported)
 	}
 	return v.V.Interface(), nil
 }
 
 func (v Value) IsNil() (bool, error) {
 	if v.V.Kind() != reflect.Chan && v.V.Kind() != reflect.Func && v.V.Kind() != reflect.Interface && v.V.Kind() != reflect.Map && v.V.Kind() != reflect.Ptr && v.V.Kind() != reflect.Slice {
-		return false, errors.New("reflect.Value.IsNil of non-pointer")
+		return false, kindError("reflect.Value.IsNil", "chan, func, interface, map, ptr or slice", v.V.Kind())
 	}
 	return v.V.IsNil(), nil
 }
@@ -216,13 +306,13 @@ func (v Value) IsZero() (bool, error) {
 	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
 		return v.IsNil()
 	default:
-		return false, errors.New("reflect.Value.IsZero of invalid")
+		return false, newError("reflect.Value.IsZero", ErrInvalidValue)
 	}
 }
 
 func (v Value) SetZero() error {
 	if !v.V.CanSet() {
-		return errors.New("reflect.Value.SetZero of unaddressable value")
+		return newError("reflect.Value.SetZero", ErrUnaddressable)
 	}
 	v.V.SetZero()
 	return nil
@@ -245,19 +335,19 @@ func (v Value) Len() (int, error) {
 			return v.V.Len(), nil
 		}
 	}
-	return 0, errors.New("reflect.Value.Len of non-array, non-slice, non-string value")
+	return 0, kindError("reflect.Value.Len", "array, slice or string", v.V.Kind())
 }
 
 func (v Value) MapIndex(key Value) (Value, error) {
 	if v.V.Kind() != reflect.Map {
-		return Value{}, errors.New("reflect.Value.MapIndex of non-map value")
+		return Value{}, kindError("reflect.Value.MapIndex", "map", v.V.Kind())
 	}
 	return Value{v.V.MapIndex(key.V)}, nil
 }
 
 func (v Value) MapKeys() ([]Value, error) {
 	if v.V.Kind() != reflect.Map {
-		return nil, errors.New("reflect.Value.MapKeys of non-map value")
+		return nil, kindError("reflect.Value.MapKeys", "map", v.V.Kind())
 	}
 	var keys []Value
 	for _, key := range v.V.MapKeys() {
@@ -266,61 +356,89 @@ func (v Value) MapKeys() ([]Value, error) {
 	return keys, nil
 }
 
-func (v Value) MapRange() (*reflect.MapIter, error) {
-	if v.V.Kind() != reflect.Map {
-		return nil, errors.New("reflect.Value.MapRange of non-map value")
-	}
-	return v.V.MapRange(), nil
-}
-
+// Method returns a function value corresponding to v's i'th method, in the method set of v's
+// type, which can be of any kind that has methods. v must not be a nil interface value.
 func (v Value) Method(i int) (Value, error) {
-	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.Method of non-struct value")
+	if err := v.checkMethods("reflect.Value.Method"); err != nil {
+		return Value{}, err
 	}
 	if i < 0 || i >= v.V.NumMethod() {
-		return Value{}, errors.New("reflect.Value.Method index out of range")
+		return Value{}, rangeError("reflect.Value.Method", i, v.V.NumMethod())
 	}
-	return Value{v.V.Method(i)}, nil
+	var out reflect.Value
+	err := Try(func() error {
+		out = v.V.Method(i)
+		return nil
+	})
+	return Value{out}, err
 }
 
+// NumMethod returns the number of methods in the method set of v's type. For an interface
+// type, it includes unexported methods.
 func (v Value) NumMethod() (int, error) {
-	if v.V.Kind() != reflect.Struct {
-		return 0, errors.New("reflect.Value.NumMethod of non-struct value")
+	if !v.V.IsValid() {
+		return 0, newError("reflect.Value.NumMethod", ErrInvalidValue)
 	}
 	return v.V.NumMethod(), nil
 }
 
+// MethodByName returns a function value corresponding to the method of v with the given name.
+// It is an error wrapping ErrNotFound if there is no such method in the method set of v's type.
 func (v Value) MethodByName(name string) (Value, error) {
-	if v.V.Kind() != reflect.Struct {
-		return Value{}, errors.New("reflect.Value.MethodByName of non-struct value")
+	if err := v.checkMethods("reflect.Value.MethodByName"); err != nil {
+		return Value{}, err
+	}
+	var out reflect.Value
+	err := Try(func() error {
+		out = v.V.MethodByName(name)
+		return nil
+	})
+	if err != nil {
+		return Value{}, err
+	}
+	if !out.IsValid() {
+		e := newError("reflect.Value.MethodByName", ErrNotFound)
+		e.Expected = "method named " + name
+		e.Actual = v.V.Type().String()
+		return Value{}, e
+	}
+	return Value{out}, nil
+}
+
+func (v Value) checkMethods(method string) error {
+	if !v.V.IsValid() {
+		return newError(method, ErrInvalidValue)
+	}
+	if v.V.Kind() == reflect.Interface && v.V.IsNil() {
+		return newError(method, ErrNilValue)
 	}
-	return Value{v.V.MethodByName(name)}, nil
+	return nil
 }
 
 func (v Value) OverflowComplex(x complex128) (bool, error) {
 	if v.V.Kind() != reflect.Complex64 && v.V.Kind() != reflect.Complex128 {
-		return false, errors.New("reflect.Value.OverflowComplex of non-complex value")
+		return false, kindError("reflect.Value.OverflowComplex", "complex", v.V.Kind())
 	}
 	return v.V.OverflowComplex(x), nil
 }
 
 func (v Value) OverflowFloat(x float64) (bool, error) {
 	if v.V.Kind() != reflect.Float32 && v.V.Kind() != reflect.Float64 {
-		return false, errors.New("reflect.Value.OverflowFloat of non-float value")
+		return false, kindError("reflect.Value.OverflowFloat", "float", v.V.Kind())
 	}
 	return v.V.OverflowFloat(x), nil
 }
 
 func (v Value) OverflowInt(x int64) (bool, error) {
 	if v.V.Kind() != reflect.Int && v.V.Kind() != reflect.Int8 && v.V.Kind() != reflect.Int16 && v.V.Kind() != reflect.Int32 && v.V.Kind() != reflect.Int64 {
-		return false, errors.New("reflect.Value.OverflowInt of non-int value")
+		return false, kindError("reflect.Value.OverflowInt", "int", v.V.Kind())
 	}
 	return v.V.OverflowInt(x), nil
 }
 
 func (v Value) OverflowUint(x uint64) (bool, error) {
 	if v.V.Kind() != reflect.Uint && v.V.Kind() != reflect.Uint8 && v.V.Kind() != reflect.Uint16 && v.V.Kind() != reflect.Uint32 && v.V.Kind() != reflect.Uint64 && v.V.Kind() != reflect.Uintptr {
-		return false, errors.New("reflect.Value.OverflowUint of non-uint value")
+		return false, kindError("reflect.Value.OverflowUint", "uint", v.V.Kind())
 	}
 	return v.V.OverflowUint(x), nil
 }
@@ -331,15 +449,15 @@ func (v Value) Pointer() (uintptr, error) {
 	case reflect.Pointer, reflect.Chan, reflect.Map, reflect.UnsafePointer, reflect.Func, reflect.Slice:
 		return v.V.Pointer(), nil
 	}
-	return 0, errors.New("reflect.Value.Pointer of non-pointer value")
+	return 0, kindError("reflect.Value.Pointer", "pointer", v.V.Kind())
 }
 
 func (v Value) Recv() (Value, bool, error) {
 	if v.V.Kind() != reflect.Chan {
-		return Value{}, false, errors.New("reflect.Value.Recv of non-chan value")
+		return Value{}, false, kindError("reflect.Value.Recv", "chan", v.V.Kind())
 	}
 	if v.V.Type().ChanDir()&reflect.RecvDir == 0 {
-		return Value{}, false, errors.New("reflect.Value.Recv of non-receivable chan")
+		return Value{}, false, typeError("reflect.Value.Recv", reflect.ChanOf(reflect.RecvDir, v.V.Type().Elem()), v.V.Type())
 	}
 	x, b := v.V.Recv()
 	return Value{x}, b, nil
@@ -347,24 +465,36 @@ func (v Value) Recv() (Value, bool, error) {
 
 func (v Value) Send(x Value) error {
 	if v.V.Kind() != reflect.Chan {
-		return errors.New("reflect.Value.Send of non-chan value")
+		return kindError("reflect.Value.Send", "chan", v.V.Kind())
 	}
 	if v.V.Type().ChanDir()&reflect.SendDir == 0 {
-		return errors.New("reflect.Value.Send of non-sendable chan")
+		return typeError("reflect.Value.Send", reflect.ChanOf(reflect.SendDir, v.V.Type().Elem()), v.V.Type())
+	}
+	if !x.V.IsValid() {
+		return newError("reflect.Value.Send", ErrInvalidValue)
+	}
+	if !v.V.CanInterface() || !x.V.CanInterface() {
+		return newError("reflect.Value.Send", ErrUnexported)
 	}
 	if v.V.Type().Elem() != x.V.Type() {
-		return errors.New("reflect.Value.Send with wrong type")
+		return typeError("reflect.Value.Send", v.V.Type().Elem(), x.V.Type())
 	}
-	v.V.Send(x.V)
-	return nil
+	err := Try(func() error {
+		v.V.Send(x.V)
+		return nil
+	})
+	if isRuntimePanic(err, "send on closed channel") {
+		return newError("reflect.Value.Send", ErrClosed)
+	}
+	return err
 }
 
 func (v Value) Set(x Value) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.Set of unaddressable value")
+		return newError("reflect.Value.Set", ErrUnaddressable)
 	}
 	if v.V.Type() != x.V.Type() {
-		return errors.New("reflect.Value.Set with wrong type")
+		return typeError("reflect.Value.Set", v.V.Type(), x.V.Type())
 	}
 	v.V.Set(x.V)
 	return nil
@@ -372,10 +502,10 @@ func (v Value) Set(x Value) error {
 
 func (v Value) SetBool(x bool) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetBool of unaddressable value")
+		return newError("reflect.Value.SetBool", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Bool {
-		return errors.New("reflect.Value.SetBool of non-bool value")
+		return kindError("reflect.Value.SetBool", "bool", v.V.Kind())
 	}
 	v.V.SetBool(x)
 	return nil
@@ -383,10 +513,10 @@ func (v Value) SetBool(x bool) error {
 
 func (v Value) SetBytes(x []byte) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetBytes of unaddressable value")
+		return newError("reflect.Value.SetBytes", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Slice || v.V.Type().Elem().Kind() != reflect.Uint8 {
-		return errors.New("reflect.Value.SetBytes of non-[]byte value")
+		return kindError("reflect.Value.SetBytes", "[]byte", v.V.Kind())
 	}
 	v.V.SetBytes(x)
 	return nil
@@ -394,10 +524,10 @@ func (v Value) SetBytes(x []byte) error {
 
 func (v Value) SetComplex(x complex128) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetComplex of unaddressable value")
+		return newError("reflect.Value.SetComplex", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Complex64 && v.V.Kind() != reflect.Complex128 {
-		return errors.New("reflect.Value.SetComplex of non-complex value")
+		return kindError("reflect.Value.SetComplex", "complex", v.V.Kind())
 	}
 	v.V.SetComplex(x)
 	return nil
@@ -405,10 +535,10 @@ func (v Value) SetComplex(x complex128) error {
 
 func (v Value) SetFloat(x float64) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetFloat of unaddressable value")
+		return newError("reflect.Value.SetFloat", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Float32 && v.V.Kind() != reflect.Float64 {
-		return errors.New("reflect.Value.SetFloat of non-float value")
+		return kindError("reflect.Value.SetFloat", "float", v.V.Kind())
 	}
 	v.V.SetFloat(x)
 	return nil
@@ -416,10 +546,10 @@ func (v Value) SetFloat(x float64) error {
 
 func (v Value) SetInt(x int64) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetInt of unaddressable value")
+		return newError("reflect.Value.SetInt", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Int && v.V.Kind() != reflect.Int8 && v.V.Kind() != reflect.Int16 && v.V.Kind() != reflect.Int32 && v.V.Kind() != reflect.Int64 {
-		return errors.New("reflect.Value.SetInt of non-int value")
+		return kindError("reflect.Value.SetInt", "int", v.V.Kind())
 	}
 	v.V.SetInt(x)
 	return nil
@@ -427,10 +557,10 @@ func (v Value) SetInt(x int64) error {
 
 func (v Value) SetLen(n int) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetLen of unaddressable value")
+		return newError("reflect.Value.SetLen", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Slice {
-		return errors.New("reflect.Value.SetLen of non-slice value")
+		return kindError("reflect.Value.SetLen", "slice", v.V.Kind())
 	}
 	v.V.SetLen(n)
 	return nil
@@ -438,10 +568,10 @@ func (v Value) SetLen(n int) error {
 
 func (v Value) SetCap(n int) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetCap of unaddressable value")
+		return newError("reflect.Value.SetCap", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Slice {
-		return errors.New("reflect.Value.SetCap of non-slice value")
+		return kindError("reflect.Value.SetCap", "slice", v.V.Kind())
 	}
 	v.V.SetCap(n)
 	return nil
@@ -449,13 +579,13 @@ func (v Value) SetCap(n int) error {
 
 func (v Value) SetMapIndex(key Value, elem Value) error {
 	if v.V.Kind() != reflect.Map {
-		return errors.New("reflect.Value.SetMapIndex of non-map value")
+		return kindError("reflect.Value.SetMapIndex", "map", v.V.Kind())
 	}
 	if v.V.Type().Key() != key.V.Type() {
-		return errors.New("reflect.Value.SetMapIndex with wrong key type")
+		return typeError("reflect.Value.SetMapIndex", v.V.Type().Key(), key.V.Type())
 	}
 	if v.V.Type().Elem() != elem.V.Type() {
-		return errors.New("reflect.Value.SetMapIndex with wrong elem type, expexted: " + v.V.Type().Elem().String() + " got: " + elem.V.Type().String())
+		return typeError("reflect.Value.SetMapIndex", v.V.Type().Elem(), elem.V.Type())
 	}
 	v.V.SetMapIndex(key.V, elem.V)
 	return nil
@@ -463,10 +593,10 @@ func (v Value) SetMapIndex(key Value, elem Value) error {
 
 func (v Value) SetUint(x uint64) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetUint of unaddressable value")
+		return newError("reflect.Value.SetUint", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Uint && v.V.Kind() != reflect.Uint8 && v.V.Kind() != reflect.Uint16 && v.V.Kind() != reflect.Uint32 && v.V.Kind() != reflect.Uint64 && v.V.Kind() != reflect.Uintptr {
-		return errors.New("reflect.Value.SetUint of non-uint value")
+		return kindError("reflect.Value.SetUint", "uint", v.V.Kind())
 	}
 	v.V.SetUint(x)
 	return nil
@@ -474,10 +604,10 @@ func (v Value) SetUint(x uint64) error {
 
 func (v Value) SetPointer(x unsafe.Pointer) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetPointer of unaddressable value")
+		return newError("reflect.Value.SetPointer", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.Ptr {
-		return errors.New("reflect.Value.SetPointer of non-pointer value")
+		return kindError("reflect.Value.SetPointer", "pointer", v.V.Kind())
 	}
 	v.V.SetPointer(x)
 	return nil
@@ -485,10 +615,10 @@ func (v Value) SetPointer(x unsafe.Pointer) error {
 
 func (v Value) SetString(x string) error {
 	if !v.CanSet() {
-		return errors.New("reflect.Value.SetString of unaddressable value")
+		return newError("reflect.Value.SetString", ErrUnaddressable)
 	}
 	if v.V.Kind() != reflect.String {
-		return errors.New("reflect.Value.SetString of non-string value")
+		return kindError("reflect.Value.SetString", "string", v.V.Kind())
 	}
 	v.V.SetString(x)
 	return nil
@@ -496,29 +626,29 @@ func (v Value) SetString(x string) error {
 
 func (v Value) Slice(i int, j int) (Value, error) {
 	if v.V.Kind() != reflect.Array && v.V.Kind() != reflect.Slice && v.V.Kind() != reflect.String {
-		return Value{}, errors.New("reflect.Value.Slice of non-array, non-slice, non-string value")
+		return Value{}, kindError("reflect.Value.Slice", "array, slice or string", v.V.Kind())
 	}
 	if i < 0 || i >= v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Slice low out of range")
+		return Value{}, rangeError("reflect.Value.Slice", i, v.V.Len())
 	}
 	if j < i || j > v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Slice high out of range")
+		return Value{}, rangeError("reflect.Value.Slice", j, v.V.Len()+1)
 	}
 	return Value{v.V.Slice(i, j)}, nil
 }
 
 func (v Value) Slice3(i int, j int, k int) (Value, error) {
 	if v.V.Kind() != reflect.Array && v.V.Kind() != reflect.Slice && v.V.Kind() != reflect.String {
-		return Value{}, errors.New("reflect.Value.Slice3 of non-array, non-slice, non-string value")
+		return Value{}, kindError("reflect.Value.Slice3", "array, slice or string", v.V.Kind())
 	}
 	if i < 0 || i >= v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Slice3 low out of range")
+		return Value{}, rangeError("reflect.Value.Slice3", i, v.V.Len())
 	}
 	if j < i || j > v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Slice3 mid out of range")
+		return Value{}, rangeError("reflect.Value.Slice3", j, v.V.Len()+1)
 	}
 	if k < j || k > v.V.Len() {
-		return Value{}, errors.New("reflect.Value.Slice3 high out of range")
+		return Value{}, rangeError("reflect.Value.Slice3", k, v.V.Len()+1)
 	}
 	return Value{v.V.Slice3(i, j, k)}, nil
 }
@@ -529,10 +659,10 @@ func (v Value) String() string {
 
 func (v Value) TryRecv() (Value, bool, error) {
 	if v.V.Kind() != reflect.Chan {
-		return Value{}, false, errors.New("reflect.Value.TryRecv of non-chan value")
+		return Value{}, false, kindError("reflect.Value.TryRecv", "chan", v.V.Kind())
 	}
 	if v.V.Type().ChanDir()&reflect.RecvDir == 0 {
-		return Value{}, false, errors.New("reflect.Value.TryRecv of non-receivable chan")
+		return Value{}, false, typeError("reflect.Value.TryRecv", reflect.ChanOf(reflect.RecvDir, v.V.Type().Elem()), v.V.Type())
 	}
 	x, b := v.V.TryRecv()
 	return Value{x}, b, nil
@@ -540,20 +670,34 @@ func (v Value) TryRecv() (Value, bool, error) {
 
 func (v Value) TrySend(x Value) (bool, error) {
 	if v.V.Kind() != reflect.Chan {
-		return false, errors.New("reflect.Value.TrySend of non-chan value")
+		return false, kindError("reflect.Value.TrySend", "chan", v.V.Kind())
 	}
 	if v.V.Type().ChanDir()&reflect.SendDir == 0 {
-		return false, errors.New("reflect.Value.TrySend of non-sendable chan")
+		return false, typeError("reflect.Value.TrySend", reflect.ChanOf(reflect.SendDir, v.V.Type().Elem()), v.V.Type())
+	}
+	if !x.V.IsValid() {
+		return false, newError("reflect.Value.TrySend", ErrInvalidValue)
+	}
+	if !v.V.CanInterface() || !x.V.CanInterface() {
+		return false, newError("reflect.Value.TrySend", ErrUnexported)
 	}
 	if v.V.Type().Elem() != x.V.Type() {
-		return false, errors.New("reflect.Value.TrySend with wrong type")
+		return false, typeError("reflect.Value.TrySend", v.V.Type().Elem(), x.V.Type())
+	}
+	var sent bool
+	err := Try(func() error {
+		sent = v.V.TrySend(x.V)
+		return nil
+	})
+	if isRuntimePanic(err, "send on closed channel") {
+		return false, newError("reflect.Value.TrySend", ErrClosed)
 	}
-	return v.V.TrySend(x.V), nil
+	return sent, err
 }
 
 func (v Value) Type() Type {
 	t := v.V.Type()
-	return &RefractType{t}
+	return toType(t)
 }
 
 func (v Value) CanUint() bool {
@@ -564,29 +708,35 @@ func (v Value) Uint() (uint64, error) {
 	if v.V.Kind() == reflect.Uint || v.V.Kind() == reflect.Uint8 || v.V.Kind() == reflect.Uint16 || v.V.Kind() == reflect.Uint32 || v.V.Kind() == reflect.Uint64 || v.V.Kind() == reflect.Uintptr {
 		return v.V.Uint(), nil
 	}
-	return 0, errors.New("reflect.Value.Uint of non-uint value")
+	return 0, kindError("reflect.Value.Uint", "uint", v.V.Kind())
 }
 
 func (v Value) UnsafeAddr() (uintptr, error) {
 	if v.V.CanAddr() {
 		return v.V.UnsafeAddr(), nil
 	}
-	return 0, errors.New("reflect.Value.UnsafeAddr of unaddressable value")
+	return 0, newError("reflect.Value.UnsafeAddr", ErrUnaddressable)
 }
 
-// this can panic if the value is a not in heap pointer
+// UnsafePointer returns v's value as an unsafe.Pointer. A panic from a not-in-heap pointer is
+// recovered and returned as a *PanicError.
 func (v Value) UnsafePointer() (unsafe.Pointer, error) {
 	switch v.V.Kind() {
 	case reflect.Ptr, reflect.UnsafePointer, reflect.Chan, reflect.Map, reflect.Func, reflect.Slice:
-		return v.V.UnsafePointer(), nil
+		var p unsafe.Pointer
+		err := Try(func() error {
+			p = v.V.UnsafePointer()
+			return nil
+		})
+		return p, err
 	default:
-		return nil, errors.New("reflect.Value.UnsafePointer of non-pointer value")
+		return nil, kindError("reflect.Value.UnsafePointer", "pointer", v.V.Kind())
 	}
 }
 
 func (v Value) Grow(n int) error {
 	if v.V.Kind() != reflect.Slice {
-		return errors.New("reflect.Value.Grow of non-slice value")
+		return kindError("reflect.Value.Grow", "slice", v.V.Kind())
 	}
 	v.V.Grow(n)
 	return nil
@@ -594,7 +744,7 @@ func (v Value) Grow(n int) error {
 
 func (v Value) Clear() error {
 	if v.V.Kind() != reflect.Slice && v.V.Kind() != reflect.Map {
-		return errors.New("reflect.Value.Clear of non-slice, non-map value")
+		return kindError("reflect.Value.Clear", "slice or map", v.V.Kind())
 	}
 	v.V.Clear()
 	return nil
@@ -602,12 +752,12 @@ func (v Value) Clear() error {
 
 func Append(s Value, x ...Value) (Value, error) {
 	if s.V.Kind() != reflect.Slice {
-		return Value{}, errors.New("reflect.Append of non-slice value")
+		return Value{}, kindError("reflect.Append", "slice", s.V.Kind())
 	}
 	var in []reflect.Value
 	for _, v := range x {
 		if v.V.Type() != s.V.Type().Elem() {
-			return Value{}, errors.New("reflect.Append with wrong type")
+			return Value{}, typeError("reflect.Append", s.V.Type().Elem(), v.V.Type())
 		}
 		in = append(in, v.V)
 	}
@@ -616,11 +766,14 @@ func Append(s Value, x ...Value) (Value, error) {
 }
 
 func AppendSlice(s Value, t Value) (Value, error) {
-	if s.V.Kind() != reflect.Slice || t.V.Kind() != reflect.Slice {
-		return Value{}, errors.New("reflect.AppendSlice of non-slice value")
+	if s.V.Kind() != reflect.Slice {
+		return Value{}, kindError("reflect.AppendSlice", "slice", s.V.Kind())
+	}
+	if t.V.Kind() != reflect.Slice {
+		return Value{}, kindError("reflect.AppendSlice", "slice", t.V.Kind())
 	}
 	if t.V.Type().Elem() != s.V.Type().Elem() {
-		return Value{}, errors.New("reflect.AppendSlice with wrong type")
+		return Value{}, typeError("reflect.AppendSlice", s.V.Type(), t.V.Type())
 	}
 	out := reflect.AppendSlice(s.V, t.V)
 	return Value{out}, nil
@@ -628,13 +781,13 @@ func AppendSlice(s Value, t Value) (Value, error) {
 
 func Copy(dst Value, src Value) (int, error) {
 	if dst.V.Kind() != reflect.Slice && dst.V.Kind() != reflect.Array {
-		return 0, errors.New("reflect.Copy to non-slice, non-array value")
+		return 0, kindError("reflect.Copy", "slice or array", dst.V.Kind())
 	}
 	var stringCopy bool
 	if src.V.Kind() != reflect.Slice && src.V.Kind() != reflect.Array {
 		stringCopy = src.V.Kind() == reflect.String && dst.V.Type().Elem().Kind() == reflect.Uint8
 		if !stringCopy {
-			return 0, errors.New("reflect.Copy from non-slice, non-array value")
+			return 0, kindError("reflect.Copy", "slice, array or string", src.V.Kind())
 		}
 	}
 	return reflect.Copy(dst.V, src.V), nil
@@ -642,16 +795,16 @@ func Copy(dst Value, src Value) (int, error) {
 
 func MakeSlice(t Type, len int, cap int) (Value, error) {
 	if t.Kind() != Slice {
-		return Value{}, errors.New("reflect.MakeSlice with non-slice type")
+		return Value{}, kindError("reflect.MakeSlice", "slice", reflect.Kind(t.Kind()))
 	}
 	if len < 0 {
-		return Value{}, errors.New("reflect.MakeSlice with negative len")
+		return Value{}, argError("reflect.MakeSlice", "non-negative len", strconv.Itoa(len))
 	}
 	if cap < 0 {
-		return Value{}, errors.New("reflect.MakeSlice with negative cap")
+		return Value{}, argError("reflect.MakeSlice", "non-negative cap", strconv.Itoa(cap))
 	}
 	if len > cap {
-		return Value{}, errors.New("reflect.MakeSlice with len > cap")
+		return Value{}, argError("reflect.MakeSlice", "len <= cap", fmt.Sprintf("len %d, cap %d", len, cap))
 	}
 	out := reflect.MakeSlice(t.ReflectType(), len, cap)
 	return Value{out}, nil
@@ -659,17 +812,17 @@ func MakeSlice(t Type, len int, cap int) (Value, error) {
 
 func MakeChan(t Type, buffer int) (Value, error) {
 	if t.Kind() != Chan {
-		return Value{}, errors.New("reflect.MakeChan with non-chan type")
+		return Value{}, kindError("reflect.MakeChan", "chan", reflect.Kind(t.Kind()))
 	}
 	if buffer < 0 {
-		return Value{}, errors.New("reflect.MakeChan with negative buffer")
+		return Value{}, argError("reflect.MakeChan", "non-negative buffer", strconv.Itoa(buffer))
 	}
 	d, err := t.ChanDir()
 	if err != nil {
 		return Value{}, err
 	}
 	if d != BothDir {
-		return Value{}, errors.New("reflect.MakeChan with unidirectional chan type")
+		return Value{}, typeError("reflect.MakeChan", reflect.ChanOf(reflect.BothDir, t.ReflectType().Elem()), t.ReflectType())
 	}
 	out := reflect.MakeChan(t.ReflectType(), buffer)
 	return Value{out}, nil
@@ -677,7 +830,7 @@ func MakeChan(t Type, buffer int) (Value, error) {
 
 func MakeMap(t Type) (Value, error) {
 	if t.Kind() != Map {
-		return Value{}, errors.New("reflect.MakeMap with non-map type")
+		return Value{}, kindError("reflect.MakeMap", "map", reflect.Kind(t.Kind()))
 	}
 	out := reflect.MakeMap(t.ReflectType())
 	return Value{out}, nil
@@ -689,7 +842,7 @@ func Indirect(v Value) Value {
 
 func Zero(t Type) (Value, error) {
 	if t.ReflectType() == nil {
-		return Value{}, errors.New("reflect.Zero with nil type")
+		return Value{}, newError("reflect.Zero", ErrNilType)
 	}
 	out := reflect.Zero(t.ReflectType())
 	return Value{out}, nil
@@ -699,22 +852,33 @@ func ZeroGeneric[T any]() T {
 	return reflect.Zero(reflect.TypeFor[T]()).Interface().(T)
 }
 
-// may panic if t is a new type that may not be allocated in heap (possibly undefined cgo C type)
+// New returns a Value representing a pointer to a new zero value for the specified type.
+// If t can not be allocated in the heap (possibly an undefined cgo C type), the panic is
+// recovered and returned as a *PanicError.
 func New(t Type) (Value, error) {
-	if t.ReflectType() == nil {
-		return Value{}, errors.New("reflect.New with nil type")
+	if t == nil || t.ReflectType() == nil {
+		return Value{}, newError("reflect.New", ErrNilType)
 	}
-	out := reflect.New(t.ReflectType())
-	return Value{out}, nil
+	var out reflect.Value
+	err := Try(func() error {
+		out = reflect.New(t.ReflectType())
+		return nil
+	})
+	return Value{out}, err
 }
 
-// may panic if t is a new type that may not be allocated in heap (possibly undefined cgo C type)
+// NewAt returns a Value representing the value of type t stored at p. Panics raised by reflect
+// for types that can not be allocated in the heap are recovered as in New.
 func NewAt(t Type, p unsafe.Pointer) (Value, error) {
-	if t.ReflectType() == nil {
-		return Value{}, errors.New("reflect.NewAt with nil type")
+	if t == nil || t.ReflectType() == nil {
+		return Value{}, newError("reflect.NewAt", ErrNilType)
 	}
-	out := reflect.NewAt(t.ReflectType(), p).Elem()
-	return Value{out}, nil
+	var out reflect.Value
+	err := Try(func() error {
+		out = reflect.NewAt(t.ReflectType(), p).Elem()
+		return nil
+	})
+	return Value{out}, err
 }
 
 func (v Value) CanConvert(t Type) bool {
@@ -723,7 +887,7 @@ func (v Value) CanConvert(t Type) bool {
 
 func (v Value) Convert(t Type) (Value, error) {
 	if !v.V.CanConvert(t.ReflectType()) {
-		return Value{}, errors.New("reflect.Value.Convert with incompatible type")
+		return Value{}, typeError("reflect.Value.Convert", t.ReflectType(), v.V.Type())
 	}
 	out := v.V.Convert(t.ReflectType())
 	return Value{out}, nil
@@ -734,8 +898,16 @@ func (v Value) Comparable() bool {
 }
 
 func (v Value) Equal(x Value) (bool, error) {
-	if !v.Comparable() || !x.Comparable() {
-		return false, errors.New("reflect.Value.Equal with incomparable value")
+	for _, u := range []Value{v, x} {
+		if !u.V.IsValid() {
+			return false, newError("reflect.Value.Equal", ErrInvalidValue)
+		}
+		if !u.Comparable() {
+			e := newError("reflect.Value.Equal", ErrWrongType)
+			e.Expected = "comparable value"
+			e.Actual = typeString(u.V.Type())
+			return false, e
+		}
 	}
 	return v.V.Equal(x.V), nil
 }
@@ -0,0 +1,106 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func vals(args ...any) []Value {
+	out := make([]Value, len(args))
+	for i, a := range args {
+		out[i] = ValueOf(a)
+	}
+	return out
+}
+
+func TestCall(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	join := func(sep string, parts ...string) string { return strings.Join(parts, sep) }
+	var nilFunc func()
+
+	tests := []struct {
+		name    string
+		fn      any
+		args    []Value
+		want    []any
+		wantErr error
+		index   int
+	}{
+		{name: "exact arguments", fn: add, args: vals(1, 2), want: []any{3}},
+		{name: "too few", fn: add, args: vals(1), wantErr: ErrInvalidArgument, index: -1},
+		{name: "too many", fn: add, args: vals(1, 2, 3), wantErr: ErrInvalidArgument, index: -1},
+		{name: "wrong type", fn: add, args: vals(1, "2"), wantErr: ErrWrongType, index: 1},
+		{name: "zero Value argument", fn: add, args: []Value{ValueOf(1), {}}, wantErr: ErrInvalidValue, index: 1},
+		{name: "variadic without extra arguments", fn: join, args: vals(","), want: []any{""}},
+		{name: "variadic with extra arguments", fn: join, args: vals(",", "a", "b"), want: []any{"a,b"}},
+		{name: "variadic missing fixed argument", fn: join, args: nil, wantErr: ErrInvalidArgument, index: -1},
+		{name: "variadic wrong element type", fn: join, args: vals(",", "a", 1), wantErr: ErrWrongType, index: 2},
+		{name: "not a func", fn: 1, args: nil, wantErr: ErrWrongKind, index: -1},
+		{name: "nil func", fn: nilFunc, args: nil, wantErr: ErrNilValue, index: -1},
+		{name: "assignable to interface", fn: func(s fmt.Stringer) string { return s.String() }, args: vals(Int), want: []any{"int"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ValueOf(tt.fn).Call(tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Call() error = %v, want %v", err, tt.wantErr)
+				}
+				var ve *ValueError
+				if !errors.As(err, &ve) || ve.Index != tt.index {
+					t.Fatalf("Call() error = %#v, want index %d", err, tt.index)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Call() error = %v", err)
+			}
+			if len(out) != len(tt.want) {
+				t.Fatalf("Call() returned %d results, want %d", len(out), len(tt.want))
+			}
+			for i, o := range out {
+				got, _ := o.Interface()
+				if got != tt.want[i] {
+					t.Errorf("result %d = %v, want %v", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCallSlice(t *testing.T) {
+	join := func(sep string, parts ...string) string { return strings.Join(parts, sep) }
+
+	tests := []struct {
+		name    string
+		fn      any
+		args    []Value
+		want    string
+		wantErr error
+	}{
+		{name: "slice as variadic argument", fn: join, args: vals("-", []string{"a", "b"}), want: "a-b"},
+		{name: "nil slice", fn: join, args: vals("-", []string(nil)), want: ""},
+		{name: "missing slice", fn: join, args: vals("-"), wantErr: ErrInvalidArgument},
+		{name: "element instead of slice", fn: join, args: vals("-", "a"), wantErr: ErrWrongType},
+		{name: "not variadic", fn: func(a int) {}, args: vals(1), wantErr: ErrWrongKind},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := ValueOf(tt.fn).CallSlice(tt.args)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("CallSlice() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CallSlice() error = %v", err)
+			}
+			if got, _ := out[0].Interface(); got != tt.want {
+				t.Errorf("CallSlice() = %v, want %q", got, tt.want)
+			}
+		})
+	}
+}
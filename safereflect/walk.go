@@ -0,0 +1,156 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// A WalkNode is a single node of a value graph visited by Walk.
+type WalkNode struct {
+	// Path locates the node from the root, in the form used by Diff and Value.Lookup. The
+	// value a pointer or interface refers to has the same path as the pointer or interface.
+	Path string
+	// Value is the node itself. It is settable when it was reached through a pointer.
+	Value Value
+	// Field describes the node when it is a struct field, and is nil otherwise.
+	Field *StructField
+	// Depth is the number of steps from the root, which has depth 0.
+	Depth int
+	// Cycle reports that the node is a pointer, map or slice that one of its ancestors already
+	// refers to. Walk does not descend into it, as doing so would never end.
+	Cycle bool
+}
+
+// Replace assigns x to the node, following the rules of Value.SetAssignable. The node must be
+// settable. After the visitor returns, Walk descends into the new value.
+func (n WalkNode) Replace(x Value) error {
+	return n.Value.SetAssignable(x)
+}
+
+// A WalkAction tells Walk how to proceed after visiting a node.
+type WalkAction int
+
+const (
+	WalkContinue WalkAction = iota // visit the children of the node
+	WalkSkip                       // skip the children of the node
+	WalkStop                       // end the walk
+)
+
+// A Visitor is called by Walk for every node. A non-nil error ends the walk and is returned by
+// Walk.
+type Visitor func(node WalkNode) (WalkAction, error)
+
+var errStopWalk = errors.New("stop walk")
+
+// Walk visits v and every value reachable from it in depth-first order, parents before their
+// children. It descends into pointers, interfaces, structs, including their unexported fields,
+// slices, arrays and maps, whose entries are visited in key order. Channels, functions and all
+// other kinds are leaves. A map key is part of the path of its entry but is not visited itself.
+//
+// Pass a pointer to v to be able to replace nodes. Map entries can never be replaced, as they
+// are not addressable.
+func Walk(v any, visit Visitor) error {
+	if visit == nil {
+		return argError("safereflect.Walk", "non-nil visitor", "nil")
+	}
+	w := &walker{visit: visit, active: make(map[walkVisit]bool)}
+	err := w.walk("", reflect.ValueOf(v), nil, 0)
+	if err == errStopWalk {
+		return nil
+	}
+	return err
+}
+
+type walkVisit struct {
+	ptr      uintptr
+	typ      reflect.Type
+	len, cap int
+}
+
+type walker struct {
+	visit  Visitor
+	active map[walkVisit]bool
+}
+
+// reference returns the key identifying what v refers to, if v is a non-nil pointer, map or
+// slice. Slices are identified by their length and capacity as well, since subslices of one
+// array share their start.
+func reference(v reflect.Value) (walkVisit, bool) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		if !v.IsNil() {
+			return walkVisit{ptr: v.Pointer(), typ: v.Type()}, true
+		}
+	case reflect.Slice:
+		if !v.IsNil() {
+			return walkVisit{ptr: v.Pointer(), typ: v.Type(), len: v.Len(), cap: v.Cap()}, true
+		}
+	}
+	return walkVisit{}, false
+}
+
+func (w *walker) walk(path string, v reflect.Value, field *StructField, depth int) error {
+	node := WalkNode{Path: path, Value: Value{v}, Field: field, Depth: depth}
+	if ref, ok := reference(v); ok {
+		node.Cycle = w.active[ref]
+	}
+	action, err := w.visit(node)
+	if err != nil {
+		return err
+	}
+	switch action {
+	case WalkContinue:
+	case WalkSkip:
+		return nil
+	case WalkStop:
+		return errStopWalk
+	default:
+		return argError("safereflect.Walk", "WalkContinue, WalkSkip or WalkStop", fmt.Sprint(int(action)))
+	}
+	if !v.IsValid() {
+		return nil
+	}
+	// The visitor may have replaced the node, so what it refers to is looked up again.
+	if ref, ok := reference(v); ok {
+		if w.active[ref] {
+			return nil
+		}
+		w.active[ref] = true
+		defer delete(w.active, ref)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return w.walk(path, v.Elem(), nil, depth+1)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := toStructField(t.Field(i))
+			if err := w.walk(fieldPath(path, f.Name), v.Field(i), &f, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := w.walk(indexPath(path, i), v.Index(i), nil, depth+1); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		keys := v.MapKeys()
+		sort.SliceStable(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, k := range keys {
+			if err := w.walk(keyPath(path, k), v.MapIndex(k), nil, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package safereflect
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type walkItem struct {
+	Name string
+	Tags map[string]int
+}
+
+type walkRoot struct {
+	Items []walkItem
+	Ptr   *walkItem
+	Any   any
+}
+
+func TestWalk(t *testing.T) {
+	root := walkRoot{
+		Items: []walkItem{{Name: "a", Tags: map[string]int{"y": 2, "x": 1}}},
+		Any:   3,
+	}
+	// visitAll records every node as path@depth:kind, as pointers and interfaces share their path
+	// with the value they refer to.
+	visitAll := func(nodes *[]string, action func(WalkNode) WalkAction) Visitor {
+		return func(n WalkNode) (WalkAction, error) {
+			*nodes = append(*nodes, fmt.Sprintf("%s@%d:%s", n.Path, n.Depth, n.Value.V.Kind()))
+			return action(n), nil
+		}
+	}
+	cont := func(WalkNode) WalkAction { return WalkContinue }
+
+	tests := []struct {
+		name   string
+		action func(WalkNode) WalkAction
+		want   []string
+	}{
+		{
+			name:   "all nodes",
+			action: cont,
+			want: []string{
+				"@0:struct",
+				"Items@1:slice",
+				"Items[0]@2:struct",
+				"Items[0].Name@3:string",
+				"Items[0].Tags@3:map",
+				`Items[0].Tags["x"]@4:int`,
+				`Items[0].Tags["y"]@4:int`,
+				"Ptr@1:ptr",
+				"Any@1:interface",
+				"Any@2:int",
+			},
+		},
+		{
+			name: "skip",
+			action: func(n WalkNode) WalkAction {
+				if n.Path == "Items" {
+					return WalkSkip
+				}
+				return WalkContinue
+			},
+			want: []string{"@0:struct", "Items@1:slice", "Ptr@1:ptr", "Any@1:interface", "Any@2:int"},
+		},
+		{
+			name: "stop",
+			action: func(n WalkNode) WalkAction {
+				if n.Path == "Items[0].Name" {
+					return WalkStop
+				}
+				return WalkContinue
+			},
+			want: []string{"@0:struct", "Items@1:slice", "Items[0]@2:struct", "Items[0].Name@3:string"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var nodes []string
+			if err := Walk(root, visitAll(&nodes, tt.action)); err != nil {
+				t.Fatalf("Walk() error = %v", err)
+			}
+			if !reflect.DeepEqual(nodes, tt.want) {
+				t.Errorf("visited\n%s\nwant\n%s", strings.Join(nodes, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestWalkFields(t *testing.T) {
+	var fields []string
+	err := Walk(walkItem{}, func(n WalkNode) (WalkAction, error) {
+		if n.Field != nil {
+			fields = append(fields, n.Field.Name+" "+n.Field.Type.String())
+		}
+		return WalkContinue, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Name string", "Tags map[string]int"}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %v, want %v", fields, want)
+	}
+}
+
+func TestWalkReplace(t *testing.T) {
+	root := &walkRoot{Items: []walkItem{{Name: "secret"}}, Ptr: &walkItem{Name: "secret"}, Any: "secret"}
+	err := Walk(root, func(n WalkNode) (WalkAction, error) {
+		if n.Value.V.Kind() == reflect.String && n.Value.CanSet() {
+			return WalkContinue, n.Replace(ValueOf("***"))
+		}
+		return WalkContinue, nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if root.Items[0].Name != "***" || root.Ptr.Name != "***" {
+		t.Errorf("names = %q, %q, want both replaced", root.Items[0].Name, root.Ptr.Name)
+	}
+	// The string held by the interface is not addressable, so it is left alone.
+	if root.Any != "secret" {
+		t.Errorf("Any = %v, want it unchanged", root.Any)
+	}
+}
+
+func TestWalkReplaceDescends(t *testing.T) {
+	root := &walkRoot{}
+	var visited []string
+	err := Walk(root, func(n WalkNode) (WalkAction, error) {
+		visited = append(visited, n.Path)
+		if n.Path == "Ptr" && n.Value.V.Kind() == reflect.Pointer {
+			return WalkContinue, n.Replace(ValueOf(&walkItem{Name: "new"}))
+		}
+		return WalkContinue, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(strings.Join(visited, " "), "Ptr.Name") {
+		t.Errorf("visited %v, want the children of the replaced pointer", visited)
+	}
+}
+
+func TestWalkCycles(t *testing.T) {
+	type node struct {
+		Next *node
+		All  []*node
+	}
+	a := &node{}
+	b := &node{Next: a}
+	a.Next = b
+	a.All = []*node{a, b}
+
+	var cycles []string
+	visits := 0
+	err := Walk(a, func(n WalkNode) (WalkAction, error) {
+		if visits++; visits > 100 {
+			return WalkStop, errors.New("walk does not end")
+		}
+		if n.Cycle {
+			cycles = append(cycles, n.Path)
+		}
+		return WalkContinue, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"Next.Next", "All[0]", "All[1].Next"}
+	if !reflect.DeepEqual(cycles, want) {
+		t.Errorf("cycles at %v, want %v", cycles, want)
+	}
+
+	// A pointer seen twice without a cycle is walked both times.
+	shared := &node{}
+	pair := []*node{shared, shared}
+	cycles = nil
+	if err := Walk(pair, func(n WalkNode) (WalkAction, error) {
+		if n.Cycle {
+			cycles = append(cycles, n.Path)
+		}
+		return WalkContinue, nil
+	}); err != nil || cycles != nil {
+		t.Errorf("Walk() = %v, cycles at %v, want no cycles", err, cycles)
+	}
+}
+
+func TestWalkErrors(t *testing.T) {
+	errVisit := errors.New("visit")
+	tests := []struct {
+		name    string
+		visit   Visitor
+		wantErr error
+	}{
+		{name: "nil visitor", visit: nil, wantErr: ErrInvalidArgument},
+		{name: "visitor error", visit: func(WalkNode) (WalkAction, error) { return WalkContinue, errVisit }, wantErr: errVisit},
+		{name: "unknown action", visit: func(WalkNode) (WalkAction, error) { return WalkAction(9), nil }, wantErr: ErrInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Walk(walkItem{}, tt.visit); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Walk() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
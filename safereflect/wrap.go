@@ -0,0 +1,95 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"time"
+)
+
+// An Invocation describes a single call of a function returned by Wrap, as seen by its
+// interceptors.
+type Invocation struct {
+	// Func is the wrapped function.
+	Func Value
+	// Args are the arguments of the call. For variadic functions the last argument is a slice
+	// holding the variadic arguments. Before hooks can modify the values or replace the slice;
+	// the arguments must still match the signature of Func.
+	Args []Value
+	// Results are the results of the call, available to After hooks, which can replace them.
+	// When the call panicked, they hold the zero values of the result types.
+	Results []Value
+	// Panic is the value the call panicked with, or nil. It is raised again once the After hooks
+	// have run, unless one of them sets it to nil, in which case Results are returned instead.
+	Panic any
+	// Start is the time the call started, after the Before hooks ran, and Duration is how long
+	// the call took, without the hooks.
+	Start    time.Time
+	Duration time.Duration
+}
+
+// An Interceptor holds hooks that run around every call of a function returned by Wrap. Either
+// hook may be nil.
+type Interceptor struct {
+	Before func(*Invocation)
+	After  func(*Invocation)
+}
+
+// Wrap returns a function of the same type as fn that calls fn with its interceptors around it.
+// The Before hooks run in the order the interceptors are given and the After hooks in the
+// reverse order, so the first interceptor is the outermost one, as with middleware. The result
+// can be type asserted back to the type of fn.
+//
+// Wrap works like MakeFunc, so when a hook leaves arguments or results that do not match the
+// signature, the call returns that error in a trailing error result. Unlike MakeFunc, Wrap also
+// accepts signatures without one, since fn already has that signature; the error is then raised
+// as a panic.
+func Wrap(fn any, interceptors ...Interceptor) (any, error) {
+	v := ValueOf(fn)
+	if !v.V.IsValid() {
+		return nil, newError("safereflect.Wrap", ErrInvalidValue)
+	}
+	if v.V.Kind() != reflect.Func {
+		return nil, kindError("safereflect.Wrap", "func", v.V.Kind())
+	}
+	if v.V.IsNil() {
+		return nil, newError("safereflect.Wrap", ErrNilValue)
+	}
+	ft := v.V.Type()
+	variadic := ft.IsVariadic()
+	w := makeFunc(ft, func(args []Value) ([]Value, error) {
+		inv := &Invocation{Func: v, Args: args}
+		for _, ic := range interceptors {
+			if ic.Before != nil {
+				ic.Before(inv)
+			}
+		}
+		inv.Start = time.Now()
+		var err error
+		if variadic {
+			inv.Results, err = v.CallSlice(inv.Args)
+		} else {
+			inv.Results, err = v.Call(inv.Args)
+		}
+		inv.Duration = time.Since(inv.Start)
+		var pe *PanicError
+		if errors.As(err, &pe) {
+			inv.Panic = pe.Value
+			inv.Results = make([]Value, ft.NumOut())
+			for i := range inv.Results {
+				inv.Results[i] = Value{reflect.Zero(ft.Out(i))}
+			}
+		} else if err != nil {
+			return nil, err
+		}
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			if interceptors[i].After != nil {
+				interceptors[i].After(inv)
+			}
+		}
+		if inv.Panic != nil {
+			panic(inv.Panic)
+		}
+		return inv.Results, nil
+	})
+	return w.V.Interface(), nil
+}
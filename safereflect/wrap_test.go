@@ -0,0 +1,147 @@
+package safereflect
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestWrapOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Interceptor {
+		return Interceptor{
+			Before: func(*Invocation) { calls = append(calls, "before "+name) },
+			After:  func(*Invocation) { calls = append(calls, "after "+name) },
+		}
+	}
+	w, err := Wrap(func() { calls = append(calls, "call") }, record("outer"), Interceptor{}, record("inner"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.(func())()
+	want := []string{"before outer", "before inner", "call", "after inner", "after outer"}
+	if !reflect.DeepEqual(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestWrapModify(t *testing.T) {
+	double := Interceptor{Before: func(inv *Invocation) {
+		inv.Args[0] = ValueOf(int(inv.Args[0].V.Int()) * 2)
+	}}
+	addOne := Interceptor{After: func(inv *Invocation) {
+		inv.Results[0] = ValueOf(int(inv.Results[0].V.Int()) + 1)
+	}}
+	w, err := Wrap(func(n int) int { return n * 10 }, double, addOne)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := w.(func(int) int)(2); got != 41 {
+		t.Errorf("wrapped(2) = %d, want 41", got)
+	}
+
+	// The variadic arguments arrive as a single slice.
+	appendC := Interceptor{Before: func(inv *Invocation) {
+		parts := inv.Args[1].V.Interface().([]string)
+		inv.Args[1] = ValueOf(append(parts, "C"))
+	}}
+	join, err := Wrap(func(sep string, parts ...string) string { return strings.Join(parts, sep) }, appendC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := join.(func(string, ...string) string)("-", "A", "B"); got != "A-B-C" {
+		t.Errorf("wrapped variadic = %q, want A-B-C", got)
+	}
+}
+
+func TestWrapInvocation(t *testing.T) {
+	var seen Invocation
+	w, err := Wrap(func(a, b int) (int, error) { return a + b, nil }, Interceptor{After: func(inv *Invocation) { seen = *inv }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, err := w.(func(int, int) (int, error))(1, 2); n != 3 || err != nil {
+		t.Fatalf("wrapped(1, 2) = %d, %v", n, err)
+	}
+	if len(seen.Args) != 2 || len(seen.Results) != 2 || seen.Start.IsZero() || seen.Duration < 0 || seen.Panic != nil {
+		t.Errorf("Invocation = %+v", seen)
+	}
+}
+
+func TestWrapPanics(t *testing.T) {
+	boom := func() int { panic("boom") }
+
+	t.Run("re-raised", func(t *testing.T) {
+		var seen any
+		w, err := Wrap(boom, Interceptor{After: func(inv *Invocation) { seen = inv.Panic }})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = Try(func() error { w.(func() int)(); return nil })
+		var pe *PanicError
+		if !errors.As(err, &pe) || pe.Value != "boom" {
+			t.Fatalf("wrapped call = %v, want it to panic with boom", err)
+		}
+		if seen != "boom" {
+			t.Errorf("After hook saw Panic = %v, want boom", seen)
+		}
+	})
+
+	t.Run("recovered", func(t *testing.T) {
+		recoverWith := Interceptor{After: func(inv *Invocation) {
+			if inv.Results[0].V.Int() != 0 {
+				t.Errorf("Results[0] = %v, want the zero value after a panic", inv.Results[0])
+			}
+			inv.Panic = nil
+			inv.Results[0] = ValueOf(-1)
+		}}
+		w, err := Wrap(boom, recoverWith)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := w.(func() int)(); got != -1 {
+			t.Errorf("wrapped() = %d, want -1", got)
+		}
+	})
+}
+
+func TestWrapMismatch(t *testing.T) {
+	badArg := Interceptor{Before: func(inv *Invocation) { inv.Args[0] = ValueOf("x") }}
+
+	w, err := Wrap(func(n int) (int, error) { return n, nil }, badArg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.(func(int) (int, error))(1); !errors.Is(err, ErrWrongType) {
+		t.Errorf("wrapped call error = %v, want %v in the error result", err, ErrWrongType)
+	}
+
+	badResult := Interceptor{After: func(inv *Invocation) { inv.Results = nil }}
+	w, err = Wrap(func() int { return 1 }, badResult)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Try(func() error { w.(func() int)(); return nil }); !errors.Is(err, ErrInvalidArgument) {
+		t.Errorf("wrapped call = %v, want a panic with %v", err, ErrInvalidArgument)
+	}
+}
+
+func TestWrapRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		fn      any
+		wantErr error
+	}{
+		{name: "nil", fn: nil, wantErr: ErrInvalidValue},
+		{name: "not a func", fn: 1, wantErr: ErrWrongKind},
+		{name: "nil func", fn: (func())(nil), wantErr: ErrNilValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Wrap(tt.fn); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Wrap() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}